@@ -0,0 +1,55 @@
+// Command levelcheck generates levels for a range of seeds and reports the levels which fail validation.
+// Every seed plays through all levels of the config with a fresh character, like a new game does.
+// With -endless the levels of the endless descent past the campaign are checked too.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/adapters/secondary/storage"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+func main() {
+	configPath := flag.String("config", storage.DungeonConfigFile, "dungeon config to generate the levels by")
+	first := flag.Int64("seed", 1, "first seed to check")
+	seeds := flag.Int("seeds", 1000, "number of seeds to check")
+	endless := flag.Int("endless", 0, "number of levels of the endless descent to check past the campaign")
+	flag.Parse()
+
+	cfg, err := storage.LoadDungeonConfig(*configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	cfg.EndlessMode = *endless > 0
+
+	failed, levels := 0, 0
+	for seed := *first; seed < *first+int64(*seeds); seed++ {
+		rng := common.NewRNG(seed)
+		for level := 1; level <= cfg.LastLevel()+*endless; level++ {
+			levels++
+			if err := checkLevel(cfg, level, rng); err != nil {
+				failed++
+				fmt.Printf("seed %d level %d:\n%v\n", seed, level, err)
+			}
+		}
+	}
+	fmt.Printf("%d of %d levels failed validation\n", failed, levels)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// checkLevel generates the level and validates it. A panic of the generator is reported as an error.
+func checkLevel(cfg *storage.Config, level int, rng *common.RNG) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("generator panicked: %v", r)
+		}
+	}()
+	d := storage.GenerateDungeonFromConfig(level, cfg, nil, rng)
+	return d.Validate()
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"flag"
+
 	"github.com/tdutanton/Rogue_Game_go/internal/services/app"
 )
 
 func main() {
-	app.Run()
+	seed := flag.Int64("seed", 0, "seed of new games, the same seed and input give the same game (0 - random)")
+	replay := flag.String("replay", "", "replay file to play back on start")
+	flag.Parse()
+
+	app.Run(*seed, *replay)
 }
@@ -2,9 +2,11 @@ package input
 
 import (
 	"context"
+	"errors"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/adapters/secondary/storage"
 	"github.com/tdutanton/Rogue_Game_go/internal/application/usecases"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 
@@ -23,6 +25,12 @@ const (
 	AppStateGameOver
 	// AppStateWin shows screen if player's win
 	AppStateWin
+	// AppStateSlotPicker shows the list of save slots to load
+	AppStateSlotPicker
+	// AppStateReplay plays a recorded run back
+	AppStateReplay
+	// AppStateOverwritePicker shows the list of save slots to overwrite with a new game when all are taken
+	AppStateOverwritePicker
 )
 
 // InputHandler handles user input and translates it into game actions.
@@ -32,8 +40,11 @@ type InputHandler struct {
 	cancel            context.CancelFunc               // function to cancel the game context
 	playerActionUC    *usecases.PlayerActionUseCase    // use case for player movements
 	inventoryActionUC *usecases.InventoryActionUseCase // use case for inventory operations
+	replayUC          *usecases.ReplayPlaybackUseCase  // use case for replay playback
 	appState          AppState                         // current AppState
 	MainWindow        *gc.Window                       // Start Screen
+	slots             []common.SaveSlotInfo            // save slots shown in the slot picker
+	newGameEndless    bool                             // mode of the new game waiting for a slot to overwrite
 }
 
 // NewInputHandler creates a new InputHandler instance.
@@ -43,6 +54,7 @@ type InputHandler struct {
 //   - cancel: context cancellation function to terminate the game
 //   - playerActionUC: use case for handling player movement actions
 //   - inventoryActionUC: use case for handling inventory actions
+//   - replayUC: use case for replay playback
 //
 // Returns:
 //   - *InputHandler: initialized input handler instance
@@ -51,6 +63,7 @@ func NewInputHandler(
 	cancel context.CancelFunc,
 	playerActionUC *usecases.PlayerActionUseCase,
 	inventoryActionUC *usecases.InventoryActionUseCase,
+	replayUC *usecases.ReplayPlaybackUseCase,
 	mainWindow *gc.Window,
 ) *InputHandler {
 	return &InputHandler{
@@ -58,6 +71,7 @@ func NewInputHandler(
 		cancel:            cancel,
 		playerActionUC:    playerActionUC,
 		inventoryActionUC: inventoryActionUC,
+		replayUC:          replayUC,
 		appState:          AppStateMainMenu,
 		MainWindow:        mainWindow,
 	}
@@ -66,8 +80,9 @@ func NewInputHandler(
 // Update processes the current input frame.
 // It reads keyboard input and triggers corresponding game actions:
 //   - Movement (WASD or arrow keys)
-//   - Inventory operations (h,j,k,e keys)
+//   - Inventory operations (h,j,k,l,e keys)
 //   - Item selection (number keys 0-9)
+//   - Replay playback controls (Space, n, +, -, q)
 //   - Game termination (Ctrl+C)
 //
 // The method uses a 100ms timeout for non-blocking input.
@@ -79,24 +94,37 @@ func (h *InputHandler) Update() {
 	case AppStateMainMenu:
 		switch key {
 		case ' ', gc.KEY_ENTER:
-			h.startNewGame()
+			h.startNewGame(false)
+		case 'e', 'E':
+			h.startNewGame(true)
 		case 'l', 'L':
-			storage.RemoveLastRecord()
-			_, err := h.playerActionUC.LoadGame()
-			if err != nil {
-				h.MainWindow.MovePrintf(2, 12, "Failed to load game - there's no save file. Please start new game")
-				h.MainWindow.Refresh()
-			} else {
-				h.appState = AppStateInGame
-				h.playerActionUC.RenderInitial()
-			}
+			h.showSaveSlots()
 		case 'q', 'Q':
 			h.cancel()
 		case 's', 'S':
 			h.showLeaderBoard()
+		case 'r', 'R':
+			h.StartReplay("")
 		case 0x03: // Ctrl+C
 			h.cancel()
 		}
+	case AppStateReplay:
+		switch key {
+		case ' ':
+			h.replayUC.TogglePause()
+		case 'n', 'N':
+			h.handleActionResult(h.replayUC.Step())
+		case '+', '=':
+			h.replayUC.Faster()
+		case '-':
+			h.replayUC.Slower()
+		case 'q', 'Q':
+			h.returnToMainMenu()
+		case 0x03: // Ctrl+C
+			h.cancel()
+		default:
+			h.handleActionResult(h.replayUC.Tick())
+		}
 	case AppStateInGame:
 		switch key {
 		case 'a', gc.KEY_LEFT:
@@ -111,8 +139,13 @@ func (h *InputHandler) Update() {
 		case 'd', gc.KEY_RIGHT:
 			result := h.playerActionUC.Execute(unit.Right)
 			h.handleActionResult(result)
+		case 'f':
+			result := h.playerActionUC.Search()
+			h.handleActionResult(result)
 		case 'h':
 			h.inventoryActionUC.Execute(item.WeaponType)
+		case 'l':
+			h.inventoryActionUC.Execute(item.ArmorType)
 		case 'j':
 			h.inventoryActionUC.Execute(item.FoodType)
 		case 'k':
@@ -125,6 +158,24 @@ func (h *InputHandler) Update() {
 		case 0x03: // Ctrl+C
 			h.cancel()
 		}
+	case AppStateSlotPicker:
+		switch key {
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			h.loadSlot(int(key - '1'))
+		case ' ', 27: // Space or Esc
+			h.returnToMainMenu()
+		case 0x03: // Ctrl+C
+			h.cancel()
+		}
+	case AppStateOverwritePicker:
+		switch key {
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			h.overwriteSlot(int(key - '1'))
+		case ' ', 27: // Space or Esc
+			h.returnToMainMenu()
+		case 0x03: // Ctrl+C
+			h.cancel()
+		}
 	case AppStateGameOver, AppStateWin:
 		switch key {
 		case ' ':
@@ -142,12 +193,90 @@ func (h *InputHandler) SetAppState(state AppState) {
 	h.appState = state
 }
 
-// startNewGame - action in main menu if player chose start new game
-func (h *InputHandler) startNewGame() {
+// startNewGame - action in main menu if player chose start new game or endless descent.
+// If all save slots are taken, the player chooses the slot to overwrite first
+func (h *InputHandler) startNewGame(endless bool) {
+	slot, err := h.playerActionUC.FreeSlot()
+	if errors.Is(err, storage.ErrNoFreeSlot) {
+		h.slots = h.playerActionUC.SaveSlots()
+		h.newGameEndless = endless
+		h.appState = AppStateOverwritePicker
+		h.playerActionUC.RenderSaveSlots(h.slots, true)
+		return
+	}
+	if err != nil {
+		h.MainWindow.MovePrintf(2, 12, "Failed to start new game - can't read the save slots")
+		h.MainWindow.Refresh()
+		return
+	}
+	h.startNewGameInSlot(slot, endless)
+}
+
+// overwriteSlot - action in overwrite picker, starts the new game in the slot with the given index in the shown list
+func (h *InputHandler) overwriteSlot(index int) {
+	if index < 0 || index >= len(h.slots) {
+		return
+	}
+	h.startNewGameInSlot(h.slots[index].Name, h.newGameEndless)
+}
+
+// startNewGameInSlot - starts the new game saved to the slot
+func (h *InputHandler) startNewGameInSlot(slot string, endless bool) {
+	if err := h.playerActionUC.NewGame(endless, slot); err != nil {
+		h.MainWindow.MovePrintf(2, 12, "Failed to start new game - can't clear the save slot")
+		h.MainWindow.Refresh()
+		return
+	}
+	h.appState = AppStateInGame
+	h.MainWindow.Erase()
+	h.MainWindow.Refresh()
+	h.playerActionUC.RenderInitial()
+}
+
+// showSaveSlots - action in main menu if player chose load game, opens the slot picker
+func (h *InputHandler) showSaveSlots() {
+	h.slots = h.playerActionUC.SaveSlots()
+	if len(h.slots) == 0 {
+		h.MainWindow.MovePrintf(2, 12, "Failed to load game - there's no save file. Please start new game")
+		h.MainWindow.Refresh()
+		return
+	}
+	h.appState = AppStateSlotPicker
+	h.playerActionUC.RenderSaveSlots(h.slots, false)
+}
+
+// loadSlot - action in slot picker, loads the slot with the given index in the shown list
+func (h *InputHandler) loadSlot(index int) {
+	if index < 0 || index >= len(h.slots) {
+		return
+	}
+	_, err := h.playerActionUC.LoadGame(h.slots[index].Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrSaveTooNew):
+			h.MainWindow.MovePrintf(2, 12, "Failed to load game - the save is from a newer game version")
+		default:
+			h.MainWindow.MovePrintf(2, 12, "Failed to load game - the save file is broken. Please choose another slot")
+		}
+		h.MainWindow.Refresh()
+		return
+	}
+	h.MainWindow.Erase()
+	h.MainWindow.Refresh()
 	h.appState = AppStateInGame
+	h.playerActionUC.RenderInitial()
+}
+
+// StartReplay - play the replay file back, an empty path means the most recent replay
+func (h *InputHandler) StartReplay(path string) {
+	if err := h.replayUC.Load(path); err != nil {
+		h.MainWindow.MovePrintf(2, 12, "Failed to play replay - there's no replay file")
+		h.MainWindow.Refresh()
+		return
+	}
+	h.appState = AppStateReplay
 	h.MainWindow.Erase()
 	h.MainWindow.Refresh()
-	h.playerActionUC.NewGame()
 	h.playerActionUC.RenderInitial()
 }
 
@@ -166,7 +295,7 @@ func (h *InputHandler) handleActionResult(result usecases.ActionResult) {
 		h.appState = AppStateGameOver
 	case usecases.Win:
 		h.appState = AppStateWin
-	case usecases.NextLevel:
+	case usecases.NextLevel, usecases.PreviousLevel:
 		h.playerActionUC.RenderInitial()
 	}
 }
@@ -1,5 +1,7 @@
 package render
 
+import "github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+
 // Color pair constants for ncurses color attributes.
 // These constants represent predefined color combinations used throughout the game.
 // The values correspond to ncurses color pair indices (1 through 6).
@@ -25,3 +27,12 @@ const (
 	// BlueBlack represents blue text on black background
 	BlueBlack
 )
+
+// colourPairs maps the colours of the entities to the color pairs on the black background.
+var colourPairs = map[common.Colour]int16{
+	common.ColourWhite:  WhiteBlack,
+	common.ColourRed:    RedBlack,
+	common.ColourGreen:  GreenBlack,
+	common.ColourYellow: YellowBlack,
+	common.ColourBlue:   BlueBlack,
+}
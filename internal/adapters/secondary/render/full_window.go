@@ -2,6 +2,7 @@ package render
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 )
@@ -34,9 +35,11 @@ func (v *View) RenderMainWindow() {
 
 	menuY, menuX := startY+logoHeight+5, startX+12
 	v.MainWindow.MovePrintf(menuY, menuX, `New Game (Press Space)`)
-	v.MainWindow.MovePrintf(menuY+1, menuX, `Load Game (Press L)`)
-	v.MainWindow.MovePrintf(menuY+2, menuX, `LeaderBoard (Press S)`)
-	v.MainWindow.MovePrintf(menuY+3, menuX, `Exit (Press Q)`)
+	v.MainWindow.MovePrintf(menuY+1, menuX, `Endless Descent (Press E)`)
+	v.MainWindow.MovePrintf(menuY+2, menuX, `Load Game (Press L)`)
+	v.MainWindow.MovePrintf(menuY+3, menuX, `LeaderBoard (Press S)`)
+	v.MainWindow.MovePrintf(menuY+4, menuX, `Last Replay (Press R)`)
+	v.MainWindow.MovePrintf(menuY+5, menuX, `Exit (Press Q)`)
 
 	leftWeapon := []string{
 		"  ,:\\      /:.",
@@ -188,15 +191,20 @@ func (v *View) RenderCharacterWinWindow() {
 	v.MainWindow.Refresh()
 }
 
-// RenderStatisticWindow - draw leaderboard on screen
-func (v *View) RenderStatisticWindow(stats []common.Stats) {
+// RenderStatisticWindow - draw leaderboard of campaign or endless runs on screen
+func (v *View) RenderStatisticWindow(stats []common.Stats, endless bool) {
 	startX, startY := 3, 3
 	v.MainWindow.Clear()
 	v.MainWindow.Box(0, 0)
+	title := "LEADERBOARD"
+	if endless {
+		title = "ENDLESS LEADERBOARD"
+	}
+	pad := (58 - len(title)) / 2
 	sword := []string{
 		"              />",
 		" ()          //---------------------------------------------------------(",
-		"(*)OXOXOXOXO(*>                      LEADERBOARD                         \\",
+		"(*)OXOXOXOXO(*>" + strings.Repeat(" ", pad) + title + strings.Repeat(" ", 58-pad-len(title)) + "\\",
 		" ()          \\\\-----------------------------------------------------------)",
 		"              \\>",
 	}
@@ -237,6 +245,50 @@ func (v *View) RenderStatisticWindow(stats []common.Stats) {
 			v.MainWindow.ColorOff(YellowBlack)
 		}
 	}
+	v.MainWindow.MovePrintf(31, 20, "Press E to switch between campaign and endless runs")
 	v.MainWindow.MovePrintf(32, 30, "Press any key to continue ...")
 	v.MainWindow.Refresh()
 }
+
+// RenderSaveSlotsWindow - draw save slot picker on screen
+func (v *View) RenderSaveSlotsWindow(slots []common.SaveSlotInfo, overwrite bool) {
+	startX, startY := 3, 5
+	v.MainWindow.Clear()
+	v.MainWindow.Box(0, 0)
+
+	v.MainWindow.ColorOn(GreenBlack)
+	if overwrite {
+		v.MainWindow.MovePrint(startY, startX, "All save slots are taken, choose the game to overwrite:")
+	} else {
+		v.MainWindow.MovePrint(startY, startX, "Choose saved game:")
+	}
+	v.MainWindow.ColorOff(GreenBlack)
+
+	header := " #   Slot       Level   Treasures   Health    Saved at           Seed"
+	v.MainWindow.MovePrint(startY+2, startX, header)
+	startY += 4
+
+	for row, slot := range slots {
+		if row%2 == 0 {
+			v.MainWindow.ColorOn(YellowBlack)
+		}
+
+		v.MainWindow.MovePrint(startY+row, startX+1, fmt.Sprintf("%d", row+1))
+		v.MainWindow.MovePrint(startY+row, startX+5, slot.Name)
+		v.MainWindow.MovePrint(startY+row, startX+16, fmt.Sprintf("%d", slot.Level))
+		v.MainWindow.MovePrint(startY+row, startX+24, fmt.Sprintf("%d", slot.Treasure))
+		v.MainWindow.MovePrint(startY+row, startX+36, fmt.Sprintf("%d(%d)", slot.Health, slot.MaxHealth))
+		v.MainWindow.MovePrint(startY+row, startX+46, slot.SavedAt.Format("2006-01-02 15:04"))
+		v.MainWindow.MovePrint(startY+row, startX+65, fmt.Sprintf("%d", slot.Seed))
+
+		if row%2 == 0 {
+			v.MainWindow.ColorOff(YellowBlack)
+		}
+	}
+	if overwrite {
+		v.MainWindow.MovePrintf(32, 20, "Press slot number to overwrite, Space to return to main menu")
+	} else {
+		v.MainWindow.MovePrintf(32, 20, "Press slot number to load, Space to return to main menu")
+	}
+	v.MainWindow.Refresh()
+}
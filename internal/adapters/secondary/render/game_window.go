@@ -18,8 +18,11 @@ func (v *View) RenderGame(d dungeon.Dungeon) {
 	}
 	v.RenderRooms(d.Rooms, d.Player)
 	v.RenderPassages(d.Passages)
+	v.RenderTraps(d.Traps)
 	v.RenderItems(d)
 	v.RenderEnemy(d)
+	v.RenderStairs(d)
+	v.RenderArtifact(d)
 	v.RenderPlayer(d)
 
 	v.RenderExit(d)
@@ -56,9 +59,10 @@ func (v *View) ClearRoomFromFog(room dungeon.Room) {
 	endX := room.X + room.Width - 1
 	endY := room.Y + room.Height - 1
 
+	floor, color := floorSymbol(room)
 	for y := startY + 1; y < endY; y++ {
 		for x := startX + 1; x < endX; x++ {
-			v.draw(y, x, EmptyFloor, GreenBlack)
+			v.draw(y, x, floor, color)
 		}
 	}
 }
@@ -79,6 +83,7 @@ func (v *View) FillRoomByPartFog(room dungeon.Room, player dungeon.Coordinator)
 	startY := room.Y + 1
 	endX := room.X + room.Width - 2
 	endY := room.Y + room.Height - 2
+	floor, color := floorSymbol(room)
 
 	for y := startY; y <= endY; y++ {
 		for x := startX; x <= endX; x++ {
@@ -91,7 +96,7 @@ func (v *View) FillRoomByPartFog(room dungeon.Room, player dungeon.Coordinator)
 				(!isVertical && absX >= ratio*absY)
 
 			if visible {
-				v.draw(y, x, EmptyFloor, GreenBlack)
+				v.draw(y, x, floor, color)
 			} else {
 				v.draw(y, x, Fog, GreenBlack)
 			}
@@ -99,6 +104,26 @@ func (v *View) FillRoomByPartFog(room dungeon.Room, player dungeon.Coordinator)
 	}
 }
 
+// floorSymbol returns the floor symbol and its color for the room.
+// Special rooms have their own floor; a shrine turns white after its blessing is received.
+func floorSymbol(room dungeon.Room) (gc.Char, int16) {
+	switch room.Type {
+	case dungeon.RoomVault:
+		return VaultFloor, YellowBlack
+	case dungeon.RoomLair:
+		return LairFloor, RedBlack
+	case dungeon.RoomShrine:
+		if room.Blessing != nil {
+			return ShrineFloor, BlueBlack
+		}
+		return ShrineFloor, WhiteBlack
+	case dungeon.RoomArena:
+		return ArenaFloor, RedBlack
+	default:
+		return EmptyFloor, GreenBlack
+	}
+}
+
 func (v *View) isCoordVisible(coord common.Coords, player dungeon.Coordinator, room dungeon.Room) bool {
 	playerCoords := player.GetCoords()
 	newX := coord.X - playerCoords.X
@@ -146,8 +171,17 @@ func (v *View) RenderRoom(room dungeon.Room, player dungeon.Coordinator) {
 	v.draw(endY, startX, UpperLeftCorner, YellowBlack)
 	v.draw(endY, endX, LowerRightCorner, YellowBlack)
 
-	for _, room := range room.Doors {
-		v.draw(room.Y, room.X, Door, YellowBlack)
+	for _, door := range room.Doors {
+		switch {
+		case door.Hidden && (door.Y == startY || door.Y == endY):
+			v.draw(door.Y, door.X, WallHorizontal, YellowBlack)
+		case door.Hidden:
+			v.draw(door.Y, door.X, WallVertical, YellowBlack)
+		case door.Lock != 0:
+			v.draw(door.Y, door.X, Door, RedBlack)
+		default:
+			v.draw(door.Y, door.X, Door, YellowBlack)
+		}
 	}
 }
 
@@ -158,10 +192,10 @@ func (v *View) RenderPassages(passages []dungeon.Passage) {
 	}
 }
 
-// RenderPassage - draw one passage
+// RenderPassage - draw one passage, secret corridors stay unseen until found
 func (v *View) RenderPassage(passage dungeon.Passage) {
 	for _, path := range passage.Path {
-		if !path.Visited {
+		if !path.Visited || path.Hidden {
 			continue
 		}
 		if path.Begin.X == path.End.X {
@@ -180,19 +214,43 @@ func (v *View) RenderPassage(passage dungeon.Passage) {
 	}
 }
 
-// RenderItems - draw items slice on the map
+// RenderTraps - draw triggered and detected traps, colored by their type
+func (v *View) RenderTraps(traps []dungeon.Trap) {
+	for _, trap := range traps {
+		if !trap.Revealed {
+			continue
+		}
+		switch trap.Type {
+		case dungeon.TrapTeleport:
+			v.draw(trap.Y, trap.X, Trap, BlueBlack)
+		case dungeon.TrapSleep:
+			v.draw(trap.Y, trap.X, Trap, GreenBlack)
+		case dungeon.TrapArrow:
+			v.draw(trap.Y, trap.X, Trap, RedBlack)
+		case dungeon.TrapDoor:
+			v.draw(trap.Y, trap.X, Trap, YellowBlack)
+		}
+	}
+}
+
+// RenderItems - draw items slice on the map, the ones in the player's room or corridor
 func (v *View) RenderItems(d dungeon.Dungeon) {
 	currentRoom := d.CurrentRoomWithWalls()
-	if currentRoom == nil {
+	currentPassage := d.CurrentPassage()
+	if currentRoom == nil && currentPassage == nil {
 		return
 	}
 
 	for _, i := range d.Items {
 		coords := i.GetCoords()
 
-		if !currentRoom.Contains(coords) ||
-			(currentRoom.Visible != dungeon.FogClean &&
-				!v.isCoordVisible(coords, d.Player, *currentRoom)) {
+		if currentRoom != nil {
+			if !currentRoom.Contains(coords) ||
+				(currentRoom.Visible != dungeon.FogClean &&
+					!v.isCoordVisible(coords, d.Player, *currentRoom)) {
+				continue
+			}
+		} else if !currentPassage.Contains(coords) {
 			continue
 		}
 
@@ -203,8 +261,14 @@ func (v *View) RenderItems(d dungeon.Dungeon) {
 			v.GameWindow.MoveAddChar(coords.Y, coords.X, Elixir)
 		case item.WeaponType:
 			v.GameWindow.MoveAddChar(coords.Y, coords.X, Weapon)
+		case item.ArmorType:
+			v.GameWindow.MoveAddChar(coords.Y, coords.X, Armor)
 		case item.ScrollType:
 			v.GameWindow.MoveAddChar(coords.Y, coords.X, Scroll)
+		case item.KeyType:
+			v.draw(coords.Y, coords.X, Key, YellowBlack)
+		case item.GoldType:
+			v.draw(coords.Y, coords.X, Gold, YellowBlack)
 		default:
 			break
 		}
@@ -234,21 +298,8 @@ func (v *View) RenderEnemy(d dungeon.Dungeon) {
 			}
 		}
 
-		switch enemy.EnemyType {
-		case unit.Zombie:
-			v.draw(coords.Y, coords.X, Zombie, GreenBlack)
-		case unit.Vampire:
-			v.draw(coords.Y, coords.X, Vampire, RedBlack)
-		case unit.Ghost:
-			if enemy.Visibility {
-				v.GameWindow.MoveAddChar(coords.Y, coords.X, Ghost)
-			}
-		case unit.Ogr:
-			v.draw(coords.Y, coords.X, Ogr, YellowBlack)
-		case unit.SnakeWizard:
-			v.GameWindow.MoveAddChar(coords.Y, coords.X, SnakeWizard)
-		default:
-			break
+		if enemy.Visibility {
+			v.draw(coords.Y, coords.X, gc.Char(enemy.Kind.Glyph), colourPairs[enemy.Kind.Colour])
 		}
 	}
 }
@@ -263,7 +314,31 @@ func (v *View) RenderPlayer(d dungeon.Dungeon) {
 	}
 }
 
-// RenderExit - draw Exit point
+// RenderStairs - draw the stairs up if they're in the player's room
+func (v *View) RenderStairs(d dungeon.Dungeon) {
+	if d.StairsUp == nil {
+		return
+	}
+	currentRoom := d.CurrentRoomWithWalls()
+	if currentRoom == nil || !currentRoom.Contains(*d.StairsUp) {
+		return
+	}
+	v.draw(d.StairsUp.Y, d.StairsUp.X, StairsUp, YellowBlack)
+}
+
+// RenderArtifact - draw the artifact if it's in the player's room
+func (v *View) RenderArtifact(d dungeon.Dungeon) {
+	if d.Artifact == nil {
+		return
+	}
+	currentRoom := d.CurrentRoomWithWalls()
+	if currentRoom == nil || !currentRoom.Contains(*d.Artifact) {
+		return
+	}
+	v.draw(d.Artifact.Y, d.Artifact.X, Artifact, BlueBlack)
+}
+
+// RenderExit - draw Exit point, a sealed exit is red and doesn't blink
 func (v *View) RenderExit(d dungeon.Dungeon) {
 	coords := d.Exit
 	currentRoom := d.CurrentRoomWithWalls()
@@ -271,6 +346,10 @@ func (v *View) RenderExit(d dungeon.Dungeon) {
 		return
 	}
 
+	if d.ExitSealed {
+		v.draw(d.Exit.Y, d.Exit.X, Exit, RedBlack)
+		return
+	}
 	v.GameWindow.AttrOn(gc.A_BLINK)
 	v.draw(d.Exit.Y, d.Exit.X, Exit, GreenBlack)
 	v.GameWindow.AttrOff(gc.A_BLINK)
@@ -1,5 +1,7 @@
 package render
 
+import "github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+
 // RenderInfo displays informational messages in the game's info window.
 // It clears the window, renders each line of text with yellow-on-black coloring,
 // and refreshes the display to make changes visible.
@@ -32,3 +34,11 @@ func (v *View) RenderInfo(eventInfo []string) {
 	v.InfoWindow.ColorOff(YellowBlack)
 	v.InfoWindow.Refresh()
 }
+
+// RenderReplayStatus displays the replay playback status at the right side of the info window.
+func (v *View) RenderReplayStatus(status string) {
+	v.InfoWindow.ColorOn(GreenBlack)
+	v.InfoWindow.MovePrint(1, common.InfoWidth-len(status)-1, status)
+	v.InfoWindow.ColorOff(GreenBlack)
+	v.InfoWindow.Refresh()
+}
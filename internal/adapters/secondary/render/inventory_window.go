@@ -147,3 +147,38 @@ func (v *View) RenderWeapon(ch *unit.Character) {
 
 	v.InventoryWindow.MovePrintf(startY+len(weapons)+5, startX, "Press any key to continue ...")
 }
+
+// RenderArmor displays the player's armor inventory like the weapons:
+// - Option 0: "Without armor" (no armor worn)
+// - Subsequent options: numbered list of available armors (starting from 1)
+//
+// Display Format:
+//
+//	You haven't armors!
+//	OR
+//	0.Without armor
+//	1.Leather Jerkin (+2 defense)
+func (v *View) RenderArmor(ch *unit.Character) {
+	startX, startY := 10, 10
+	armors := ch.Inventory.Armors
+	v.InventoryWindow.MovePrintf(startY, startX, "Choose armor:")
+	if len(armors) == 0 {
+		v.InventoryWindow.MovePrintf(startY+2, startX, "You haven't armors!")
+	} else {
+		if ch.CurrentArmor == nil {
+			v.InventoryWindow.MovePrintf(startY+2, startX, "> 0.Without armor (+0 defense)")
+		} else {
+			v.InventoryWindow.MovePrintf(startY+2, startX, "  0.Without armor (+0 defense)")
+		}
+	}
+	for i, armor := range armors {
+		if ch.CurrentArmor != nil && armor == *ch.CurrentArmor {
+			v.InventoryWindow.MovePrintf(startY+i+3, startX, "> ")
+		} else {
+			v.InventoryWindow.MovePrintf(startY+i+3, startX, "  ")
+		}
+		v.InventoryWindow.MovePrintf(startY+i+3, startX+2, fmt.Sprintf("%d.%s (+%d defense)", i+1, armor.Name, armor.Defense))
+	}
+
+	v.InventoryWindow.MovePrintf(startY+len(armors)+5, startX, "Press any key to continue ...")
+}
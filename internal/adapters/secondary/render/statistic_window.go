@@ -2,6 +2,7 @@ package render
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 )
@@ -13,6 +14,7 @@ import (
 //   - Health status (current/max)
 //   - Agility score
 //   - Strength score
+//   - Active status effects, if any
 //
 // The information is displayed in red text on black background for high visibility.
 // Window is cleared before rendering and refreshed afterward to ensure proper display.
@@ -38,6 +40,23 @@ func (v *View) RenderStatistic(player unit.Character) {
 		player.Agility,
 		player.Strength,
 	)
+	statistic += fmt.Sprintf("  Lvl:%v XP:%v", player.Level, player.XP)
+	if player.Defense > 0 {
+		statistic += fmt.Sprintf("  Defense:%v", player.Defense)
+	}
+	if speed := player.CurrentSpeed(); speed != unit.NormalSpeed {
+		statistic += fmt.Sprintf("  Speed:%v", speed)
+	}
+	if len(player.Inventory.Keys) > 0 {
+		statistic += fmt.Sprintf("  Keys:%v", len(player.Inventory.Keys))
+	}
+	if len(player.Statuses) > 0 {
+		statuses := make([]string, len(player.Statuses))
+		for i, status := range player.Statuses {
+			statuses[i] = status.Type.String()
+		}
+		statistic += "  Status:" + strings.Join(statuses, ",")
+	}
 
 	v.StatisticWindow.ColorOn(RedBlack)
 	v.StatisticWindow.MovePrint(1, 7, statistic)
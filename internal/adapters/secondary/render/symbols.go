@@ -10,16 +10,10 @@ const (
 	Food   = 'f' // Symbol for food items
 	Elixir = 'e' // Symbol for elixir/potion items
 	Weapon = 'w' // Symbol for weapon items
+	Armor  = 'a' // Symbol for armor items
 	Scroll = 's' // Symbol for scroll items
-)
-
-// Enemy symbols used for rendering different enemy types.
-const (
-	Zombie      = 'Z' // Symbol for zombie enemies
-	Vampire     = 'V' // Symbol for vampire enemies
-	Ghost       = 'G' // Symbol for ghost enemies
-	Ogr         = 'O' // Symbol for ogre enemies
-	SnakeWizard = 'S' // Symbol for snake wizard enemies
+	Key    = 'k' // Symbol for keys
+	Gold   = '$' // Symbol for piles of gold
 )
 
 // Dungeon structure symbols using ncurses ACS characters.
@@ -34,6 +28,13 @@ const (
 	Passage          = gc.ACS_CKBOARD  // Passage or tunnel block ▒
 	Door             = gc.ACS_PLUS     // Door symbol +
 	EmptyFloor       = ' '             // Empty floor space
+	VaultFloor       = ':'             // Floor of a treasure vault
+	LairFloor        = ','             // Floor of a monster lair
+	ShrineFloor      = '~'             // Floor of a shrine
+	ArenaFloor       = '='             // Floor of the boss arena
 	Exit             = 'E'             // Dungeon exit symbol
+	StairsUp         = '<'             // Stairs up to the previous level
+	Artifact         = '&'             // The artifact of the campaign
 	Fog              = '.'             // Unexplored area/for symbol
+	Trap             = '^'             // Triggered or detected trap
 )
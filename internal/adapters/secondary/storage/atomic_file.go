@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveBackups specifies how many previous versions of a save slot are kept as backups
+const SaveBackups = 3
+
+// backupPath returns the path of the n-th backup of the file (1 is the newest).
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak%d", path, n)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory, flushes it to disk
+// and renames it over path. A crash in the middle of writing never leaves a truncated file at path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// writeFileWithBackups atomically replaces the save file at path, keeping its previous
+// SaveBackups valid versions as path.bak1 (newest) ... path.bakN (oldest).
+func writeFileWithBackups(path string, data []byte, perm os.FileMode) error {
+	if _, err := os.Stat(path); err == nil {
+		if err := rotateBackups(path); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, data, perm)
+}
+
+// rotateBackups shifts every backup of the file by one, dropping the oldest,
+// and copies the current file to the newest backup.
+// The current file is copied, not moved, so path stays valid until it's replaced.
+// A current file which isn't a valid save is not rotated, so it never pushes a valid backup out.
+func rotateBackups(path string) error {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := decodeSave(current); err != nil {
+		return nil
+	}
+	for n := SaveBackups - 1; n >= 1; n-- {
+		err := os.Rename(backupPath(path, n), backupPath(path, n+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeFileAtomic(backupPath(path, 1), current, 0o644)
+}
+
+// removeWithBackups deletes the file and all of its backups. Missing files are ignored.
+func removeWithBackups(path string) error {
+	for n := 1; n <= SaveBackups; n++ {
+		if err := os.Remove(backupPath(path, n)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// syncDir flushes the directory entry after a rename. Errors are ignored,
+// because not every platform allows to sync a directory.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
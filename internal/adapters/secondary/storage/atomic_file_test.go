@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// chdirTemp runs the test in an empty temporary directory, so the saves are written there.
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// saveLevel saves the slot with nothing but the level number.
+func saveLevel(t *testing.T, s *JSONDungeonStorage, slot string, level int) {
+	t.Helper()
+	var data DungeonData
+	data.LevelNumber = level
+	if err := s.SaveGameState(slot, data); err != nil {
+		t.Fatalf("save level %d: %v", level, err)
+	}
+}
+
+// savedLevel returns the level number of the save file, 0 if the file is missing.
+func savedLevel(t *testing.T, path string) int {
+	t.Helper()
+	data, err := loadSaveFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("load %s: %v", path, err)
+	}
+	return data.LevelNumber
+}
+
+func TestSaveGameStateRotatesBackups(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	for level := 1; level <= SaveBackups+2; level++ {
+		saveLevel(t, s, "slot_1", level)
+	}
+
+	path := filepath.Join(SavesDir, "slot_1.json")
+	if got := savedLevel(t, path); got != SaveBackups+2 {
+		t.Errorf("slot has level %d, want %d", got, SaveBackups+2)
+	}
+	for n := 1; n <= SaveBackups; n++ {
+		if got, want := savedLevel(t, backupPath(path, n)), SaveBackups+2-n; got != want {
+			t.Errorf("backup %d has level %d, want %d", n, got, want)
+		}
+	}
+	if _, err := os.Stat(backupPath(path, SaveBackups+1)); !os.IsNotExist(err) {
+		t.Errorf("backup %d is kept", SaveBackups+1)
+	}
+}
+
+func TestLoadGameStateFallsBackToBackup(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	saveLevel(t, s, "slot_1", 1)
+	saveLevel(t, s, "slot_1", 2)
+	if err := os.WriteFile(filepath.Join(SavesDir, "slot_1.json"), []byte(`{"level": 2, `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := s.LoadGameState("slot_1")
+	if err != nil {
+		t.Fatalf("LoadGameState: %v", err)
+	}
+	if data.LevelNumber != 1 {
+		t.Errorf("loaded level %d, want the backup with level 1", data.LevelNumber)
+	}
+}
+
+func TestLoadGameStateDoesNotFallBackFromNewerVersion(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	saveLevel(t, s, "slot_1", 1)
+	saveLevel(t, s, "slot_1", 2)
+	if err := os.WriteFile(filepath.Join(SavesDir, "slot_1.json"), []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.LoadGameState("slot_1")
+	var versionErr *SaveVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("LoadGameState error = %v, want *SaveVersionError", err)
+	}
+}
+
+func TestCorruptSaveIsNotRotated(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	saveLevel(t, s, "slot_1", 1)
+	saveLevel(t, s, "slot_1", 2)
+	path := filepath.Join(SavesDir, "slot_1.json")
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saveLevel(t, s, "slot_1", 3)
+
+	if got := savedLevel(t, path); got != 3 {
+		t.Errorf("slot has level %d, want 3", got)
+	}
+	if got := savedLevel(t, backupPath(path, 1)); got != 1 {
+		t.Errorf("newest backup has level %d, want the valid save of level 1", got)
+	}
+	if got := savedLevel(t, backupPath(path, 2)); got != 0 {
+		t.Errorf("backup 2 has level %d, want no backup", got)
+	}
+}
+
+func TestSaveGameStateRoundTrip(t *testing.T) {
+	cfg := loadTestConfig(t)
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+
+	rng := common.NewRNG(7)
+	d := GenerateDungeonFromConfig(cfg.LastLevel(), cfg, nil, rng)
+	data := DungeonToDTO(d)
+	if err := s.SaveGameState("slot_1", data); err != nil {
+		t.Fatalf("SaveGameState: %v", err)
+	}
+	loaded, err := s.LoadGameState("slot_1")
+	if err != nil {
+		t.Fatalf("LoadGameState: %v", err)
+	}
+
+	if loaded.Meta.Name != "slot_1" || loaded.Meta.Level != cfg.LastLevel() || loaded.Meta.Seed != 7 {
+		t.Errorf("slot meta %+v doesn't describe the saved game", loaded.Meta)
+	}
+	data.Version = CurrentSaveVersion
+	data.Meta = loaded.Meta
+	want, _ := json.Marshal(data)
+	got, _ := json.Marshal(loaded)
+	if !bytes.Equal(got, want) {
+		t.Errorf("loaded save differs from the saved one\ngot:  %s\nwant: %s", got, want)
+	}
+
+	restored := DTOToDungeon(*loaded)
+	if got, want := restored.RNG.Int63(), rng.Int63(); got != want {
+		t.Errorf("restored generator draws %d, want %d", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"sort"
+
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/inventory"
@@ -29,7 +31,7 @@ func DTOToSize(sd SizeData) common.Size {
 }
 
 // ItemToDTO converts a domain Item to its DTO representation.
-// Handles all item types (Elixir, Scroll, Food, Weapon) with type-specific fields.
+// Handles all item types (Elixir, Scroll, Food, Weapon, Armor, Key, Gold) with type-specific fields.
 func ItemToDTO(i item.Item) ItemData {
 	if i == nil {
 		return ItemData{}
@@ -44,6 +46,7 @@ func ItemToDTO(i item.Item) ItemData {
 		result.Agility = v.Agility
 		result.Strength = v.Strength
 		result.MaxHealth = v.MaxHealth
+		result.Speed = v.Speed
 		result.Duration = v.Duration
 		result.IsActive = v.IsActive
 	case *item.Scroll:
@@ -56,6 +59,12 @@ func ItemToDTO(i item.Item) ItemData {
 		result.Value = v.Value
 	case *item.Weapon:
 		result.Strength = v.Strength
+	case *item.Armor:
+		result.Defense = v.Defense
+	case *item.Key:
+		result.Lock = v.Lock
+	case *item.Gold:
+		result.Amount = v.Amount
 	}
 	return result
 }
@@ -76,6 +85,7 @@ func DTOToItem(id ItemData) item.Item {
 			Agility:   id.Agility,
 			Strength:  id.Strength,
 			MaxHealth: id.MaxHealth,
+			Speed:     id.Speed,
 			Coords:    common.Coords(id.CoordsData),
 			Duration:  id.Duration,
 			IsActive:  id.IsActive,
@@ -96,6 +106,23 @@ func DTOToItem(id ItemData) item.Item {
 			Strength: id.Strength,
 			Coords:   common.Coords(id.CoordsData),
 		}
+	case int(item.ArmorType):
+		return &item.Armor{
+			Name:    id.Name,
+			Defense: id.Defense,
+			Coords:  common.Coords(id.CoordsData),
+		}
+	case int(item.KeyType):
+		return &item.Key{
+			Name:   id.Name,
+			Lock:   id.Lock,
+			Coords: common.Coords(id.CoordsData),
+		}
+	case int(item.GoldType):
+		return &item.Gold{
+			Amount: id.Amount,
+			Coords: common.Coords(id.CoordsData),
+		}
 	default:
 		panic("unknown item type")
 	}
@@ -113,6 +140,7 @@ func StatsToDTO(s common.Stats) StatsData {
 		HitsMade:          s.HitsMade,
 		HitsMissed:        s.HitsMissed,
 		CellsPassed:       s.CellsPassed,
+		Endless:           s.Endless,
 	}
 }
 
@@ -128,6 +156,20 @@ func DTOToStats(sd StatsData) common.Stats {
 		HitsMade:          sd.HitsMade,
 		HitsMissed:        sd.HitsMissed,
 		CellsPassed:       sd.CellsPassed,
+		Endless:           sd.Endless,
+	}
+}
+
+// DTOToSlotInfo converts save slot metadata DTO to domain format.
+func DTOToSlotInfo(sd SlotMetaData) common.SaveSlotInfo {
+	return common.SaveSlotInfo{
+		Name:      sd.Name,
+		Level:     sd.Level,
+		Treasure:  sd.Treasure,
+		Health:    sd.Health,
+		MaxHealth: sd.MaxHealth,
+		Seed:      sd.Seed,
+		SavedAt:   sd.SavedAt,
 	}
 }
 
@@ -153,6 +195,7 @@ func InventoryToDTO(i inventory.Inventory) InventoryData {
 			Agility:    elixir.Agility,
 			Strength:   elixir.Strength,
 			MaxHealth:  elixir.MaxHealth,
+			Speed:      elixir.Speed,
 			Duration:   elixir.Duration,
 			IsActive:   elixir.IsActive,
 		})
@@ -177,6 +220,22 @@ func InventoryToDTO(i inventory.Inventory) InventoryData {
 			Strength:   weapon.Strength,
 		})
 	}
+	for _, armor := range i.Armors {
+		result.Armors = append(result.Armors, ItemData{
+			Type:       int(armor.Type()),
+			CoordsData: CoordsData(armor.GetCoords()),
+			Name:       armor.Info(),
+			Defense:    armor.Defense,
+		})
+	}
+	for _, key := range i.Keys {
+		result.Keys = append(result.Keys, ItemData{
+			Type:       int(key.Type()),
+			CoordsData: CoordsData(key.GetCoords()),
+			Name:       key.Info(),
+			Lock:       key.Lock,
+		})
+	}
 	return result
 }
 
@@ -198,6 +257,7 @@ func DTOToInventory(dto InventoryData) *inventory.Inventory {
 			Agility:   elixirDTO.Agility,
 			Strength:  elixirDTO.Strength,
 			MaxHealth: elixirDTO.MaxHealth,
+			Speed:     elixirDTO.Speed,
 			Coords:    common.Coords(elixirDTO.CoordsData),
 			Duration:  elixirDTO.Duration,
 			IsActive:  elixirDTO.IsActive,
@@ -221,42 +281,78 @@ func DTOToInventory(dto InventoryData) *inventory.Inventory {
 			Coords:   common.Coords(weaponDTO.CoordsData),
 		})
 	}
+	for _, armorDTO := range dto.Armors {
+		inv.Armors = append(inv.Armors, item.Armor{
+			Name:    armorDTO.Name,
+			Defense: armorDTO.Defense,
+			Coords:  common.Coords(armorDTO.CoordsData),
+		})
+	}
+	for _, keyDTO := range dto.Keys {
+		inv.Keys = append(inv.Keys, item.Key{
+			Name:   keyDTO.Name,
+			Lock:   keyDTO.Lock,
+			Coords: common.Coords(keyDTO.CoordsData),
+		})
+	}
 	return inv
 }
 
 // UnitToDTO converts basic unit attributes to DTO format.
 func UnitToDTO(u unit.Unit) UnitData {
+	var statuses []StatusData
+	for _, st := range u.Statuses {
+		statuses = append(statuses, StatusData{Type: int(st.Type), Duration: st.Duration, Power: st.Power})
+	}
 	return UnitData{
 		Health:     u.Health,
 		Agility:    u.Agility,
 		Strength:   u.Strength,
+		Defense:    u.Defense,
+		Speed:      u.Speed,
+		Energy:     u.Energy,
 		CoordsData: CoordsData(u.Coords),
 		InBattle:   u.InBattle,
+		Statuses:   statuses,
 	}
 }
 
 // DTOToUnit converts unit DTO back to domain format.
 func DTOToUnit(ud UnitData) unit.Unit {
+	var statuses []unit.Status
+	for _, st := range ud.Statuses {
+		statuses = append(statuses, unit.Status{Type: unit.StatusType(st.Type), Duration: st.Duration, Power: st.Power})
+	}
 	return unit.Unit{
 		Health:   ud.Health,
 		Agility:  ud.Agility,
 		Strength: ud.Strength,
+		Defense:  ud.Defense,
+		Speed:    ud.Speed,
+		Energy:   ud.Energy,
 		Coords:   common.Coords(ud.CoordsData),
 		InBattle: ud.InBattle,
+		Statuses: statuses,
 	}
 }
 
 // CharacterToDTO converts player character to DTO format.
-// Includes unit attributes, inventory, and statistics.
+// Includes unit attributes, equipment, inventory, and statistics.
 func CharacterToDTO(c unit.Character) CharacterData {
-	var weaponDTO ItemData
+	var weaponDTO, armorDTO ItemData
 	if c.CurrentWeapon != nil {
 		weaponDTO = ItemToDTO(c.CurrentWeapon)
 	}
+	if c.CurrentArmor != nil {
+		armorDTO = ItemToDTO(c.CurrentArmor)
+	}
 	return CharacterData{
 		Unit:          UnitToDTO(c.Unit),
 		MaxHealth:     c.MaxHealth,
+		Level:         c.Level,
+		XP:            c.XP,
 		CurrentWeapon: weaponDTO,
+		CurrentArmor:  armorDTO,
 		Inventory:     InventoryToDTO(c.Inventory),
 		Stats:         StatsToDTO(c.Stats),
 	}
@@ -274,23 +370,30 @@ func DTOToCharacter(cd CharacterData) unit.Character {
 	} else {
 		weapon = item.Weapon{}
 	}
+	var armor *item.Armor
+	if cd.CurrentArmor.Type == int(item.ArmorType) {
+		armor = DTOToItem(cd.CurrentArmor).(*item.Armor)
+	}
 	return unit.Character{
 		Unit:          DTOToUnit(cd.Unit),
 		MaxHealth:     cd.MaxHealth,
+		Level:         cd.Level,
+		XP:            cd.XP,
 		CurrentWeapon: &weapon,
+		CurrentArmor:  armor,
 		Inventory:     *DTOToInventory(cd.Inventory),
 		Stats:         DTOToStats(cd.Stats),
 	}
 }
 
 // RoomToDTO converts dungeon room to DTO format.
-// Includes room coordinates, size, doors, and type.
+// Includes room coordinates, size, doors, type and the shrine blessing.
 func RoomToDTO(r dungeon.Room) RoomData {
-	d := make([]CoordsData, len(r.Doors))
+	d := make([]DoorData, len(r.Doors))
 	for i, v := range r.Doors {
-		d[i] = CoordsToDTO(v.Coords)
+		d[i] = DoorData{CoordsData: CoordsToDTO(v.Coords), Lock: v.Lock, Hidden: v.Hidden}
 	}
-	return RoomData{
+	result := RoomData{
 		CoordsData: CoordsToDTO(r.Coords),
 		SizeData:   SizeToDTO(r.Size),
 		Doors:      d,
@@ -298,13 +401,23 @@ func RoomToDTO(r dungeon.Room) RoomData {
 		Visited:    r.Visited,
 		Visible:    int(r.Visible),
 	}
+	if r.Blessing != nil {
+		result.Blessing = &BlessingData{
+			MaxHealth: r.Blessing.MaxHealth,
+			Strength:  r.Blessing.Strength,
+			Agility:   r.Blessing.Agility,
+		}
+	}
+	return result
 }
 
 // DTOToRoom converts room DTO back to domain format.
 func DTOToRoom(rd RoomData) dungeon.Room {
 	d := make([]dungeon.Door, len(rd.Doors))
 	for i, v := range rd.Doors {
-		d[i].Coords = DTOtoCoords(v)
+		d[i].Coords = DTOtoCoords(v.CoordsData)
+		d[i].Lock = v.Lock
+		d[i].Hidden = v.Hidden
 	}
 	room := dungeon.Room{
 		Doors:   d,
@@ -312,6 +425,13 @@ func DTOToRoom(rd RoomData) dungeon.Room {
 		Visited: rd.Visited,
 		Visible: dungeon.Visibility(rd.Visible),
 	}
+	if rd.Blessing != nil {
+		room.Blessing = &dungeon.Blessing{
+			MaxHealth: rd.Blessing.MaxHealth,
+			Strength:  rd.Blessing.Strength,
+			Agility:   rd.Blessing.Agility,
+		}
+	}
 	room.Size = DTOToSize(rd.SizeData)
 	room.Coords = DTOtoCoords(rd.CoordsData)
 	return room
@@ -323,6 +443,7 @@ func CorridorToDTO(c dungeon.Corridor) CorridorData {
 		Begin:   CoordsData(c.Begin),
 		End:     CoordsData(c.End),
 		Visited: c.Visited,
+		Hidden:  c.Hidden,
 	}
 }
 
@@ -332,6 +453,7 @@ func DTOToCorridor(cd CorridorData) dungeon.Corridor {
 		Begin:   common.Coords(cd.Begin),
 		End:     common.Coords(cd.End),
 		Visited: cd.Visited,
+		Hidden:  cd.Hidden,
 	}
 }
 
@@ -341,7 +463,7 @@ func PassageToDTO(p dungeon.Passage) PassageData {
 	for i, v := range p.Path {
 		result[i] = CorridorToDTO(v)
 	}
-	return PassageData{Path: result}
+	return PassageData{Path: result, Cave: p.Cave}
 }
 
 // DTOToPassage converts passage DTO back to domain format.
@@ -350,20 +472,35 @@ func DTOToPassage(pd PassageData) dungeon.Passage {
 	for i, v := range pd.Path {
 		result[i] = DTOToCorridor(v)
 	}
-	return dungeon.Passage{Path: result}
+	return dungeon.Passage{Path: result, Cave: pd.Cave}
 }
 
 // EnemyToDTO converts enemy unit to DTO format.
-// Includes enemy-specific attributes like type, behavior flags, and treasure.
+// Includes enemy-specific attributes like type, behavior flags, treasure and loot.
 func EnemyToDTO(e unit.Enemy) EnemyData {
-	return EnemyData{
+	result := EnemyData{
 		Unit:       UnitToDTO(e.Unit),
-		EnemyType:  int(e.EnemyType),
+		Kind:       EnemyKindToDTO(e.Kind),
 		Animosity:  e.Animosity,
 		Visibility: e.Visibility,
 		IsPursuing: e.IsPursuing,
 		Treasure:   e.Treasure,
+		XP:         e.XP,
+		Phase:      e.Phase,
+	}
+	for _, it := range e.Loot {
+		result.Loot = append(result.Loot, ItemToDTO(it))
+	}
+	for _, p := range e.Phases {
+		result.Phases = append(result.Phases, BossPhaseData{
+			Health:    p.Health,
+			Strength:  p.Strength,
+			Agility:   p.Agility,
+			Behaviour: int(p.Behaviour),
+			Message:   p.Message,
+		})
 	}
+	return result
 }
 
 // DTOToEnemy converts enemy DTO back to domain format.
@@ -371,22 +508,27 @@ func EnemyToDTO(e unit.Enemy) EnemyData {
 func DTOToEnemy(ed EnemyData) unit.Enemy {
 	result := unit.Enemy{
 		Unit:       DTOToUnit(ed.Unit),
-		EnemyType:  unit.EnemyType(ed.EnemyType),
+		Kind:       DTOToEnemyKind(ed.Kind),
 		Animosity:  ed.Animosity,
 		Visibility: ed.Visibility,
 		IsPursuing: ed.IsPursuing,
 		Treasure:   ed.Treasure,
+		XP:         ed.XP,
+		Phase:      ed.Phase,
+	}
+	for _, it := range ed.Loot {
+		result.Loot = append(result.Loot, DTOToItem(it))
+	}
+	for _, p := range ed.Phases {
+		result.Phases = append(result.Phases, unit.BossPhase{
+			Health:    p.Health,
+			Strength:  p.Strength,
+			Agility:   p.Agility,
+			Behaviour: unit.BossBehaviour(p.Behaviour),
+			Message:   p.Message,
+		})
 	}
-	switch result.EnemyType {
-	case unit.Ghost:
-		result.DefaultMover = unit.GhostMoving{}
-	case unit.SnakeWizard:
-		result.DefaultMover = unit.SnakeWizardMoving{}
-	case unit.Ogr:
-		result.DefaultMover = unit.OgrMoving{}
-	default:
-		result.DefaultMover = unit.NonMoving{}
-	}
+	result.DefaultMover = result.KindMover()
 	if result.IsPursuing {
 		result.Mover = unit.PursuingMoving{}
 	} else {
@@ -395,18 +537,86 @@ func DTOToEnemy(ed EnemyData) unit.Enemy {
 	return result
 }
 
-// DungeonToDTO converts complete dungeon state to DTO format.
-// Includes rooms, passages, items, enemies, and player character.
-// Panics if player type assertion fails.
-func DungeonToDTO(d dungeon.Dungeon) DungeonData {
-	player, ok := d.Player.(*unit.Character)
-	if !ok {
-		panic("wrong player type")
+// EnemyKindToDTO converts the kind of an enemy to DTO format.
+func EnemyKindToDTO(k unit.EnemyKind) EnemyKindData {
+	result := EnemyKindData{
+		Name:   k.Name,
+		Title:  k.Title,
+		Glyph:  string(k.Glyph),
+		Colour: k.Colour.Name(),
+		Mover:  k.Mover,
 	}
-	result := DungeonData{
-		LevelNumber: d.LevelNumber,
-		Exit:        CoordsToDTO(d.Exit),
-		Player:      CharacterToDTO(*player),
+	for _, t := range k.Traits {
+		result.Traits = append(result.Traits, t.Name())
+	}
+	for _, hit := range k.OnHit {
+		result.OnHit = append(result.OnHit, OnHitData(hit))
+	}
+	return result
+}
+
+// DTOToEnemyKind converts the kind DTO back to domain format.
+// The colour and the traits are resolved by their names; an unknown colour is white, unknown traits are dropped.
+func DTOToEnemyKind(kd EnemyKindData) unit.EnemyKind {
+	colour, _ := common.ColourByName(kd.Colour)
+	result := unit.EnemyKind{
+		Name:   kd.Name,
+		Title:  kd.Title,
+		Colour: colour,
+		Mover:  kd.Mover,
+	}
+	if glyph := []rune(kd.Glyph); len(glyph) > 0 {
+		result.Glyph = glyph[0]
+	}
+	for _, name := range kd.Traits {
+		if t, err := unit.TraitByName(name); err == nil {
+			result.Traits = append(result.Traits, t)
+		}
+	}
+	for _, hit := range kd.OnHit {
+		result.OnHit = append(result.OnHit, unit.OnHit(hit))
+	}
+	return result
+}
+
+// TrapToDTO converts dungeon trap to DTO format.
+func TrapToDTO(t dungeon.Trap) TrapData {
+	return TrapData{
+		CoordsData: CoordsToDTO(t.Coords),
+		Type:       int(t.Type),
+		Damage:     t.Damage,
+		Revealed:   t.Revealed,
+	}
+}
+
+// DTOToTrap converts trap DTO back to domain format.
+func DTOToTrap(td TrapData) dungeon.Trap {
+	return dungeon.Trap{
+		Coords:   DTOtoCoords(td.CoordsData),
+		Type:     dungeon.TrapType(td.Type),
+		Damage:   td.Damage,
+		Revealed: td.Revealed,
+	}
+}
+
+// LevelToDTO converts the layout and the entities of a dungeon level to DTO format.
+// Panics if an enemy type assertion fails.
+func LevelToDTO(d dungeon.Dungeon) LevelData {
+	result := LevelData{
+		LevelNumber:   d.LevelNumber,
+		Exit:          CoordsToDTO(d.Exit),
+		SearchChance:  d.SearchChance,
+		ExitSealed:    d.ExitSealed,
+		BossDefeated:  d.BossDefeated,
+		ArtifactTaken: d.ArtifactTaken,
+	}
+	if d.StairsUp != nil {
+		stairs := CoordsToDTO(*d.StairsUp)
+		result.StairsUp = &stairs
+	}
+	if d.Artifact != nil {
+		artifact := CoordsToDTO(*d.Artifact)
+		result.Artifact = &artifact
 	}
 	result.Rooms = [common.MaxRoomCount]RoomData{}
 	for i, v := range d.Rooms {
@@ -428,41 +638,125 @@ func DungeonToDTO(d dungeon.Dungeon) DungeonData {
 		}
 		e[i] = EnemyToDTO(*enemy)
 	}
+	t := make([]TrapData, len(d.Traps))
+	for i, v := range d.Traps {
+		t[i] = TrapToDTO(v)
+	}
+	for _, key := range d.HeldKeys {
+		result.HeldKeys = append(result.HeldKeys, ItemToDTO(&key))
+	}
 	result.Passages = p
 	result.Items = it
 	result.Enemies = e
+	result.Traps = t
 	return result
 }
 
-// DTOToDungeon converts dungeon DTO back to complete domain representation.
-// Reconstructs all dungeon components including entities and their relationships.
-func DTOToDungeon(dd DungeonData) dungeon.Dungeon {
+// DTOToLevel converts level DTO back to a dungeon without the player and the random generator.
+func DTOToLevel(ld LevelData) dungeon.Dungeon {
 	var rooms [common.MaxRoomCount]dungeon.Room
-	for i, v := range dd.Rooms {
+	for i, v := range ld.Rooms {
 		rooms[i] = DTOToRoom(v)
 	}
-	passages := make([]dungeon.Passage, len(dd.Passages))
-	for i, v := range dd.Passages {
+	passages := make([]dungeon.Passage, len(ld.Passages))
+	for i, v := range ld.Passages {
 		passages[i] = DTOToPassage(v)
 	}
-	items := make([]item.Item, len(dd.Items))
-	for i, v := range dd.Items {
+	items := make([]item.Item, len(ld.Items))
+	for i, v := range ld.Items {
 		items[i] = DTOToItem(v)
 	}
-	enemies := make([]dungeon.Coordinator, len(dd.Enemies))
-	for i, v := range dd.Enemies {
+	enemies := make([]dungeon.Coordinator, len(ld.Enemies))
+	for i, v := range ld.Enemies {
 		enemy := DTOToEnemy(v)
 		enemies[i] = new(unit.Enemy)
 		*enemies[i].(*unit.Enemy) = enemy
 	}
-	player := DTOToCharacter(dd.Player)
+	traps := make([]dungeon.Trap, len(ld.Traps))
+	for i, v := range ld.Traps {
+		traps[i] = DTOToTrap(v)
+	}
+	var keys []item.Key
+	for _, v := range ld.HeldKeys {
+		keys = append(keys, item.Key{Name: v.Name, Lock: v.Lock, Coords: DTOtoCoords(v.CoordsData)})
+	}
+	var stairs *common.Coords
+	if ld.StairsUp != nil {
+		c := DTOtoCoords(*ld.StairsUp)
+		stairs = &c
+	}
+	var artifact *common.Coords
+	if ld.Artifact != nil {
+		c := DTOtoCoords(*ld.Artifact)
+		artifact = &c
+	}
 	return dungeon.Dungeon{
-		LevelNumber: dd.LevelNumber,
-		Rooms:       rooms,
-		Passages:    passages,
-		Exit:        DTOtoCoords(dd.Exit),
-		Player:      &player,
-		Items:       items,
-		Enemies:     enemies,
+		LevelNumber:   ld.LevelNumber,
+		Rooms:         rooms,
+		Passages:      passages,
+		Exit:          DTOtoCoords(ld.Exit),
+		Items:         items,
+		Enemies:       enemies,
+		SearchChance:  ld.SearchChance,
+		Traps:         traps,
+		ExitSealed:    ld.ExitSealed,
+		BossDefeated:  ld.BossDefeated,
+		StairsUp:      stairs,
+		Artifact:      artifact,
+		ArtifactTaken: ld.ArtifactTaken,
+		HeldKeys:      keys,
 	}
 }
+
+// FloorsToDTO converts the visited levels to DTO format, sorted by the level number.
+func FloorsToDTO(floors dungeon.Floors) []LevelData {
+	levels := make([]int, 0, len(floors))
+	for level := range floors {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	result := make([]LevelData, 0, len(floors))
+	for _, level := range levels {
+		result = append(result, LevelToDTO(floors[level]))
+	}
+	return result
+}
+
+// DTOToFloors converts the visited levels DTO back to the store of levels.
+// The levels get the player and the random generator of the current level when the player comes back.
+func DTOToFloors(lds []LevelData) dungeon.Floors {
+	floors := make(dungeon.Floors, len(lds))
+	for _, ld := range lds {
+		floors[ld.LevelNumber] = DTOToLevel(ld)
+	}
+	return floors
+}
+
+// DungeonToDTO converts the current level with the player character to DTO format.
+// The visited levels are kept by the caller and added separately.
+// Panics if player type assertion fails.
+func DungeonToDTO(d dungeon.Dungeon) DungeonData {
+	player, ok := d.Player.(*unit.Character)
+	if !ok {
+		panic("wrong player type")
+	}
+	result := DungeonData{
+		LevelData: LevelToDTO(d),
+		Player:    CharacterToDTO(*player),
+	}
+	if d.RNG != nil {
+		result.Seed = d.RNG.Seed()
+		result.RandomDraws = d.RNG.Draws()
+	}
+	return result
+}
+
+// DTOToDungeon converts dungeon DTO back to the current level with the player character.
+// The random generator is restored to the saved state, so the game continues reproducibly.
+func DTOToDungeon(dd DungeonData) dungeon.Dungeon {
+	d := DTOToLevel(dd.LevelData)
+	player := DTOToCharacter(dd.Player)
+	d.Player = &player
+	d.RNG = common.RestoreRNG(dd.Seed, dd.RandomDraws)
+	return d
+}
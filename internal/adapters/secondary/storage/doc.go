@@ -5,7 +5,8 @@
 //
 // The package offers:
 //   - Dungeon configuration loading (YAML)
-//   - Game state management (JSON persistence)
+//   - Game state management (JSON persistence in named save slots)
+//   - Versioned save format with step by step migrations of old saves
 //   - Leaderboard functionality
 //   - DTO conversions between domains
 //   - Procedural dungeon generation logic
@@ -22,17 +23,21 @@
 //	  	cfg, err := storage.LoadDungeonConfig("config.yaml")
 //
 //	  Generate new dungeon:
-//	  	dungeon := storage.GenerateDungeonFromConfig(1, cfg, nil)
-//	  	(1 - is level number - you can put enything else)
+//	  	dungeon := storage.GenerateDungeonFromConfig(1, cfg, nil, common.NewRNG(seed))
+//	  	(1 - is level number - you can put enything else,
+//	  	 the same seed always gives the same dungeon)
 //
 //	  Initialize storage:
 //	  	store := storage.NewJSONDungeonStorage()
 //
-//	  Save Game:
-//			store.SaveGameState(dungeon)
+//	  Save Game (to the named slot):
+//			store.SaveGameState("slot_1", dungeon)
 //
-//	  Load Game (last try):
-//	  	loadedDungeon, err := stor.LoadGameState()
+//	  List saved games:
+//	  	slots, err := stor.ListSaveSlots()
+//
+//	  Load Game (from the named slot):
+//	  	loadedDungeon, err := stor.LoadGameState(slots[0].Name)
 //
 //	  Update Leader Board:
 //	  		err = stor.SaveLeaderboard(common.Stats(storDTO.Player.Stats))
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 )
 
@@ -28,44 +30,65 @@ type ItemData struct {
 	Value      int             `json:"value_food,omitempty"` // Nutritional value (for food items)
 	Duration   int             `json:"duration,omitempty"`   // Effect duration in turns
 	IsActive   bool            `json:"is_active,omitempty"`  // Active state flag
+	Lock       int             `json:"lock,omitempty"`       // Number of the lock opened by the key
+	Defense    int             `json:"defense,omitempty"`    // Damage absorbed by the armor
+	Speed      int             `json:"speed,omitempty"`      // Speed change of the elixir
+	Amount     int             `json:"amount,omitempty"`     // Gold in the pile
 }
 
 // StatsData tracks various player statistics and achievements.
 type StatsData struct {
-	TreasuresReceived int `json:"treasures"`        // Total treasures collected
-	LevelAchieved     int `json:"level_achieved"`   // Highest level reached
-	EnemiesDefeated   int `json:"enemies_defeated"` // Total enemies defeated
-	FoodEaten         int `json:"food_eaten"`       // Total food consumed
-	ElixirsDrunk      int `json:"elixirs_drunk"`    // Total elixirs consumed
-	ScrollsRead       int `json:"scrolls_read"`     // Total scrolls used
-	HitsMade          int `json:"hits_ok"`          // Successful attacks
-	HitsMissed        int `json:"hits_missed"`      // Missed attacks
-	CellsPassed       int `json:"cells_passed"`     // Total movement steps taken
+	TreasuresReceived int  `json:"treasures"`         // Total treasures collected
+	LevelAchieved     int  `json:"level_achieved"`    // Highest level reached
+	EnemiesDefeated   int  `json:"enemies_defeated"`  // Total enemies defeated
+	FoodEaten         int  `json:"food_eaten"`        // Total food consumed
+	ElixirsDrunk      int  `json:"elixirs_drunk"`     // Total elixirs consumed
+	ScrollsRead       int  `json:"scrolls_read"`      // Total scrolls used
+	HitsMade          int  `json:"hits_ok"`           // Successful attacks
+	HitsMissed        int  `json:"hits_missed"`       // Missed attacks
+	CellsPassed       int  `json:"cells_passed"`      // Total movement steps taken
+	Endless           bool `json:"endless,omitempty"` // The run is in the endless mode
 }
 
 // InventoryData represents the player's inventory, containing various item categories.
 type InventoryData struct {
 	Weapons  []ItemData `json:"weapons,omitempty"` // Collected weapons
+	Armors   []ItemData `json:"armors,omitempty"`  // Collected armors
 	Elixirs  []ItemData `json:"elixirs,omitempty"` // Collected elixirs
 	Scrolls  []ItemData `json:"scrolls,omitempty"` // Collected scrolls
 	Foods    []ItemData `json:"foods,omitempty"`   // Collected food items
+	Keys     []ItemData `json:"keys,omitempty"`    // Keys of locked doors
 	Treasure int        `json:"treasure"`          // Current treasure amount
 }
 
 // UnitData contains common attributes for both characters and enemies.
 type UnitData struct {
-	Health     int             `json:"health"`   // Current health points
-	Agility    int             `json:"agility"`  // Agility attribute
-	Strength   int             `json:"strength"` // Strength attribute
+	Health     int             `json:"health"`            // Current health points
+	Agility    int             `json:"agility"`           // Agility attribute
+	Strength   int             `json:"strength"`          // Strength attribute
+	Defense    int             `json:"defense,omitempty"` // Damage absorbed by the worn armor
+	Speed      int             `json:"speed"`             // Energy gained every turn
+	Energy     int             `json:"energy"`            // Energy accumulated for the next action
 	CoordsData `json:"coords"` // Current position
-	InBattle   bool            `json:"in_battle"` // Battle engagement status
+	InBattle   bool            `json:"in_battle"`          // Battle engagement status
+	Statuses   []StatusData    `json:"statuses,omitempty"` // Active status effects
+}
+
+// StatusData describes a status effect on a unit.
+type StatusData struct {
+	Type     int `json:"type"`            // Status type identifier
+	Duration int `json:"duration"`        // Turns left, -1 for a status used up by its effect
+	Power    int `json:"power,omitempty"` // Strength of the effect
 }
 
 // CharacterData represents the player character with all associated data.
 type CharacterData struct {
 	Unit          UnitData      `json:"base_data"`        // Basic unit attributes
 	MaxHealth     int           `json:"max_health"`       // Maximum health capacity
+	Level         int           `json:"level"`            // Experience level
+	XP            int           `json:"xp"`               // Total experience
 	CurrentWeapon ItemData      `json:"weapon,omitempty"` // Currently equipped weapon
+	CurrentArmor  ItemData      `json:"armor,omitempty"`  // Currently worn armor
 	Inventory     InventoryData `json:"backpack"`         // Player inventory
 	Stats         StatsData     `json:"stats"`            // Game statistics
 }
@@ -74,42 +97,130 @@ type CharacterData struct {
 type RoomData struct {
 	SizeData   `json:"size"`                       // Room dimensions
 	CoordsData `json:"room_up_left_corner_coords"` // Top-left corner coordinates
-	Doors      []CoordsData                        `json:"doors"`   // Door locations
-	Type       int                                 `json:"type"`    // Room type identifier
-	Visited    bool                                `json:"visited"` // Room visited identified
-	Visible    int                                 `json:"visible"` // Room visible identifier
+	Doors      []DoorData                          `json:"doors"`              // Doors of the room
+	Type       int                                 `json:"type"`               // Room type identifier
+	Visited    bool                                `json:"visited"`            // Room visited identified
+	Visible    int                                 `json:"visible"`            // Room visible identifier
+	Blessing   *BlessingData                       `json:"blessing,omitempty"` // Blessing of the shrine which isn't received yet
+}
+
+// BlessingData describes the permanent bonus of a shrine.
+type BlessingData struct {
+	MaxHealth int `json:"max_health,omitempty"` // Max health bonus
+	Strength  int `json:"strength,omitempty"`   // Strength bonus
+	Agility   int `json:"agility,omitempty"`    // Agility bonus
+}
+
+// DoorData describes a door in a room wall.
+type DoorData struct {
+	CoordsData      // Door location
+	Lock       int  `json:"lock,omitempty"`   // Number of the lock, 0 for an open door
+	Hidden     bool `json:"hidden,omitempty"` // Secret door which hasn't been found yet
 }
 
 // CorridorData represents a connecting path between two points.
 type CorridorData struct {
-	Begin   CoordsData `json:"begin"`   // Starting coordinates
-	End     CoordsData `json:"end"`     // Ending coordinates
-	Visited bool       `json:"visited"` // Corridor visited identifier
+	Begin   CoordsData `json:"begin"`            // Starting coordinates
+	End     CoordsData `json:"end"`              // Ending coordinates
+	Visited bool       `json:"visited"`          // Corridor visited identifier
+	Hidden  bool       `json:"hidden,omitempty"` // Secret corridor which hasn't been found yet
 }
 
 // PassageData contains a series of connected corridors forming a path.
 type PassageData struct {
-	Path []CorridorData `json:"corridors"` // Sequence of corridor segments
+	Path []CorridorData `json:"corridors"`      // Sequence of corridor segments
+	Cave bool           `json:"cave,omitempty"` // Passage is an open cave area
+}
+
+// TrapData represents a trap on the room floor.
+type TrapData struct {
+	CoordsData      // Trap location
+	Type       int  `json:"type"`               // Trap type identifier
+	Damage     int  `json:"damage,omitempty"`   // Damage of an arrow trap
+	Revealed   bool `json:"revealed,omitempty"` // Trap has been triggered or detected
 }
 
 // EnemyData represents an enemy entity with combat attributes and behavior flags.
 type EnemyData struct {
-	Unit       UnitData `json:"base_data"`   // Basic unit attributes
-	EnemyType  int      `json:"enemy_type"`  // Enemy classification
-	Animosity  int      `json:"animosity"`   // Aggressiveness level
-	Visibility bool     `json:"visibility"`  // Visibility status
-	IsPursuing bool     `json:"is_pursuing"` // Pursuit behavior flag
-	Treasure   int      `json:"treasure"`    // Treasure carried by enemy
+	Unit       UnitData        `json:"base_data"`        // Basic unit attributes
+	Kind       EnemyKindData   `json:"kind"`             // Definition of the enemy
+	Animosity  int             `json:"animosity"`        // Aggressiveness level
+	Visibility bool            `json:"visibility"`       // Visibility status
+	IsPursuing bool            `json:"is_pursuing"`      // Pursuit behavior flag
+	Treasure   int             `json:"treasure"`         // Treasure carried by enemy
+	Loot       []ItemData      `json:"loot,omitempty"`   // Items dropped when the enemy is killed
+	XP         int             `json:"xp,omitempty"`     // Experience for killing the enemy
+	Phases     []BossPhaseData `json:"phases,omitempty"` // Phases of the boss fight
+	Phase      int             `json:"phase,omitempty"`  // Index of the current boss phase
+}
+
+// EnemyKindData describes the kind of an enemy.
+type EnemyKindData struct {
+	Name   string      `json:"name"`             // Config name of the kind
+	Title  string      `json:"title"`            // Name shown in the game messages
+	Glyph  string      `json:"glyph"`            // Letter on the game screen
+	Colour string      `json:"colour"`           // Config name of the colour
+	Mover  string      `json:"mover,omitempty"`  // Name of the default movement
+	Traits []string    `json:"traits,omitempty"` // Config names of the traits
+	OnHit  []OnHitData `json:"on_hit,omitempty"` // Effects of the landed hits
+}
+
+// OnHitData describes an effect of the enemy's landed hit.
+type OnHitData struct {
+	Effect   string `json:"effect"`             // Name of the effect
+	Chance   int    `json:"chance"`             // Chance in percent
+	Duration int    `json:"duration,omitempty"` // Turns of a status effect
+	Power    [2]int `json:"power"`              // Strength of the effect [min, max]
+}
+
+// BossPhaseData describes a phase of the boss fight.
+type BossPhaseData struct {
+	Health    int    `json:"health"`            // The phase begins when the boss health falls to this value
+	Strength  int    `json:"strength"`          // Strength of the boss in the phase
+	Agility   int    `json:"agility"`           // Agility of the boss in the phase
+	Behaviour int    `json:"behaviour"`         // Boss behaviour identifier
+	Message   string `json:"message,omitempty"` // Shown when the phase begins
+}
+
+// SlotMetaData is a short summary of a save slot.
+// It's stored together with the game state so the slot list can be shown without converting the whole dungeon.
+type SlotMetaData struct {
+	Name      string    `json:"name"`       // Slot name
+	Level     int       `json:"level"`      // Dungeon level at the moment of saving
+	Treasure  int       `json:"treasure"`   // Collected treasure at the moment of saving
+	Health    int       `json:"health"`     // Character's health at the moment of saving
+	MaxHealth int       `json:"max_health"` // Character's max health at the moment of saving
+	Seed      int64     `json:"seed"`       // Seed of the saved game
+	SavedAt   time.Time `json:"saved_at"`   // Time of saving
+}
+
+// LevelData contains the layout and the entities of a dungeon level.
+type LevelData struct {
+	LevelNumber   int                           `json:"level"`                    // Dungeon level number
+	Rooms         [common.MaxRoomCount]RoomData `json:"rooms"`                    // Array of rooms in the dungeon
+	Passages      []PassageData                 `json:"passages"`                 // Connecting passages between rooms
+	Exit          CoordsData                    `json:"exit"`                     // Dungeon exit location
+	Items         []ItemData                    `json:"items"`                    // Items present in the dungeon
+	Enemies       []EnemyData                   `json:"enemies"`                  // Enemies present in the dungeon
+	SearchChance  int                           `json:"search_chance"`            // Chance in percent to find a secret by one search on the level
+	Traps         []TrapData                    `json:"traps,omitempty"`          // Traps of the level
+	ExitSealed    bool                          `json:"exit_sealed,omitempty"`    // Exit is sealed until the boss is defeated
+	BossDefeated  bool                          `json:"boss_defeated,omitempty"`  // The boss of the level has been killed
+	StairsUp      *CoordsData                   `json:"stairs_up,omitempty"`      // Stairs up to the previous level
+	Artifact      *CoordsData                   `json:"artifact,omitempty"`       // The artifact of the campaign lying on the level
+	ArtifactTaken bool                          `json:"artifact_taken,omitempty"` // The player has picked up the artifact of the level
+	HeldKeys      []ItemData                    `json:"held_keys,omitempty"`      // Keys of the level carried by the player while on another level
 }
 
-// DungeonData contains all information about a dungeon level.
-// It includes the layout, entities, and player state.
+// DungeonData contains all information about the game: the current level with the player
+// and the visited levels the player has left.
+// The fields of the current level are stored at the top level of the JSON document.
 type DungeonData struct {
-	LevelNumber int                           `json:"level"`     // Current dungeon level
-	Rooms       [common.MaxRoomCount]RoomData `json:"rooms"`     // Array of rooms in the dungeon
-	Passages    []PassageData                 `json:"passages"`  // Connecting passages between rooms
-	Exit        CoordsData                    `json:"exit"`      // Dungeon exit location
-	Player      CharacterData                 `json:"character"` // Player character data
-	Items       []ItemData                    `json:"items"`     // Items present in the dungeon
-	Enemies     []EnemyData                   `json:"enemies"`   // Enemies present in the dungeon
+	Version     int           `json:"version"` // Save format version
+	Meta        SlotMetaData  `json:"meta"`    // Save slot summary
+	LevelData                 // Current dungeon level
+	Player      CharacterData `json:"character"`        // Player character data
+	Seed        int64         `json:"seed"`             // Seed of the game session random generator
+	RandomDraws uint64        `json:"draws"`            // Number of values drawn from the generator
+	Floors      []LevelData   `json:"floors,omitempty"` // Visited levels the player has left
 }
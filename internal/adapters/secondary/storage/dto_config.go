@@ -1,19 +1,34 @@
 package storage
 
+import "github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+
 // Config represents the main configuration structure for the game.
 // It contains all the initial parameters, level definitions, item effects, and enemy configurations.
 type Config struct {
 	CharacterStartParams Character         `yaml:"character_start_params"`
+	Campaign             CampaignConfig    `yaml:"campaign"`
+	Endless              EndlessConfig     `yaml:"endless"`
+	Combat               CombatConfig      `yaml:"combat"`
+	Leveling             LevelingConfig    `yaml:"leveling"`
 	Levels               []Level           `yaml:"levels"`
 	Elixir               ItemEffects       `yaml:"elixir"`
 	Scroll               ItemEffects       `yaml:"scroll"`
 	Food                 FoodEffects       `yaml:"food"`
 	Weapon               WeaponEffects     `yaml:"weapon"`
+	Armor                ArmorEffects      `yaml:"armor"`
+	Key                  KeyEffects        `yaml:"key"`
+	Vault                VaultConfig       `yaml:"vault"`
+	Lair                 LairConfig        `yaml:"lair"`
+	Shrine               ShrineEffects     `yaml:"shrine"`
+	Trap                 TrapEffects       `yaml:"trap"`
+	Boss                 BossConfig        `yaml:"boss"`
 	EnemyAgility         map[string][2]int `yaml:"enemy_agility"`
 	EnemyStrength        map[string][2]int `yaml:"enemy_strength"`
 	EnemyAnimosity       map[string][2]int `yaml:"enemy_animosity"`
 	EnemyHealth          map[string][2]int `yaml:"enemy_health"`
 	Enemies              map[string]Enemy  `yaml:"enemies"`
+
+	EndlessMode bool `yaml:"-"` // the run is an endless descent without the last level, set by the game mode
 }
 
 // Character defines the base attributes for a game character.
@@ -22,15 +37,69 @@ type Character struct {
 	Health    int `yaml:"health"`     // Current health points
 	Strength  int `yaml:"strength"`   // Strength attribute
 	Agility   int `yaml:"agility"`    // Agility attribute
+	Speed     int `yaml:"speed"`      // Energy gained every turn, 100 acts once a turn
+}
+
+// CampaignConfig defines the length of the campaign and how it's won.
+type CampaignConfig struct {
+	Levels   int    `yaml:"levels"`   // Number of levels, every level must be covered by a level range
+	Victory  string `yaml:"victory"`  // Victory condition on the last level: reach_exit, kill_boss or retrieve_artifact
+	Artifact string `yaml:"artifact"` // Name of the artifact to retrieve from the last level
+}
+
+// EndlessConfig defines how the levels past the last level range get harder in the endless mode.
+// The values of the last range grow by their curves with every level past it.
+type EndlessConfig struct {
+	EnemyStats ScalingCurve `yaml:"enemy_stats"` // Health, strength and agility of the enemies
+	EnemyCount ScalingCurve `yaml:"enemy_count"` // Number of enemies [min, max]
+	Treasure   ScalingCurve `yaml:"treasure"`    // Treasure carried by the enemies [min, max]
+}
+
+// ScalingCurve defines the growth of a value with the depth past the last level range.
+type ScalingCurve struct {
+	Curve string  `yaml:"curve"` // Name of the curve: linear, exponential or logarithmic; no growth if empty
+	Rate  float64 `yaml:"rate"`  // Growth rate of the curve, a share of the base value
+}
+
+// CombatConfig defines the combat formula: the chance to hit, the damage variance and critical hits.
+type CombatConfig struct {
+	BaseHitChance  float64 `yaml:"base_hit_chance"` // Chance to hit a defender of the same agility [0, 1]
+	AgilityStep    float64 `yaml:"agility_step"`    // Change of the chance to hit per point of the agility difference
+	MinHitChance   float64 `yaml:"min_hit_chance"`  // Lowest chance to hit [0, 1]
+	MaxHitChance   float64 `yaml:"max_hit_chance"`  // Highest chance to hit [0, 1]
+	DamageVariance float64 `yaml:"damage_variance"` // Share the damage varies by up and down [0, 1)
+	CritChance     float64 `yaml:"crit_chance"`     // Chance of a critical hit [0, 1]
+	CritMultiplier float64 `yaml:"crit_multiplier"` // Damage multiplier of a critical hit, at least 1
+}
+
+// LevelingConfig defines the progression of the player by the experience for killed enemies.
+type LevelingConfig struct {
+	BaseXP    int `yaml:"base_xp"`    // Experience for the second level, every next level needs base_xp more
+	MaxHealth int `yaml:"max_health"` // Max health gained with every level
+	Strength  int `yaml:"strength"`   // Strength gained with every level
+	Agility   int `yaml:"agility"`    // Agility gained with every level
 }
 
 // Level contains configuration for a specific game level or range of levels.
 type Level struct {
-	Range        [2]int         `yaml:"range"`         // Level range this configuration applies to [min, max]
-	EnemyChances map[string]int `yaml:"enemy_chances"` // Probability weights for different enemies
-	EnemyCount   [2]int         `yaml:"enemy_count"`   // Number of enemies [min, max]
-	ItemsCount   [2]int         `yaml:"items_count"`   // Number of items [min, max]
-	Treasure     [2]int         `yaml:"treasure"`      // Treasure amount range [min, max]
+	Range           [2]int         `yaml:"range"`             // Level range this configuration applies to [min, max]
+	EnemyChances    map[string]int `yaml:"enemy_chances"`     // Probability weights for different enemies
+	EnemyCount      [2]int         `yaml:"enemy_count"`       // Number of enemies [min, max]
+	ItemsCount      [2]int         `yaml:"items_count"`       // Number of items [min, max]
+	Treasure        [2]int         `yaml:"treasure"`          // Treasure amount range [min, max]
+	Generator       string         `yaml:"generator"`         // Name of the level generator: grid (default), bsp or cave
+	ExtraLinks      float64        `yaml:"extra_connections"` // Share of extra loop passages between neighbouring rooms [0, 1]
+	DeadEnds        int            `yaml:"dead_ends"`         // Number of dead-end corridor stubs
+	LockedDoors     int            `yaml:"locked_doors"`      // Number of locked doors, each with its key on the level
+	SecretDoors     int            `yaml:"secret_doors"`      // Number of secret doors, found by searching
+	SecretCorridors int            `yaml:"secret_corridors"`  // Number of secret corridor segments, found by searching
+	SearchChance    int            `yaml:"search_chance"`     // Chance in percent to find a secret next to the player by one search
+	RoomWeights     map[string]int `yaml:"room_weights"`      // Probability weights of plain, vault, lair and shrine rooms
+	Traps           [2]int         `yaml:"traps"`             // Number of hidden traps [min, max]
+	TrapChances     map[string]int `yaml:"trap_chances"`      // Probability weights of teleport, sleep, arrow and trapdoor traps
+	Map             string         `yaml:"map"`               // ASCII map file of a hand-authored level, relative to the config directory
+
+	layout *dungeon.ASCIIMap // parsed map, loaded with the config
 }
 
 // ItemEffects defines the possible effects of consumable items.
@@ -40,6 +109,8 @@ type ItemEffects struct {
 	Agility   []int    `yaml:"agility"`    // Possible agility modifications
 	Name      []string `yaml:"name"`       // Possible item names
 	Duration  []int    `yaml:"duration"`   // Effect durations in turns
+	Haste     []int    `yaml:"haste"`      // Possible speed gains, elixirs only
+	Slow      []int    `yaml:"slow"`       // Possible speed losses, elixirs only
 }
 
 // FoodEffects defines the effects of food items.
@@ -48,12 +119,82 @@ type FoodEffects struct {
 	Name   []string `yaml:"name"`   // Possible food names
 }
 
-// Enemy defines a specific enemy instance with references to its attribute segments.
+// Enemy defines a kind of enemies: its look, references to its attribute segments, movement and special rules.
 type Enemy struct {
-	EnemyAgility   string `yaml:"enemy_agility"`   // Reference to agility segment
-	EnemyStrength  string `yaml:"enemy_strength"`  // Reference to strength segment
-	EnemyAnimosity string `yaml:"enemy_animosity"` // Reference to animosity segment
-	EnemyHealth    string `yaml:"enemy_health"`    // Reference to health segment
+	Title          string         `yaml:"title"`           // Name shown in the game messages
+	Glyph          string         `yaml:"glyph"`           // Capital letter on the game screen and in ASCII maps
+	Colour         string         `yaml:"colour"`          // Colour on the game screen: white, red, green, yellow or blue
+	EnemyAgility   string         `yaml:"enemy_agility"`   // Reference to agility segment
+	EnemyStrength  string         `yaml:"enemy_strength"`  // Reference to strength segment
+	EnemyAnimosity string         `yaml:"enemy_animosity"` // Reference to animosity segment
+	EnemyHealth    string         `yaml:"enemy_health"`    // Reference to health segment
+	XP             int            `yaml:"xp"`              // Experience for killing the enemy
+	Speed          int            `yaml:"speed"`           // Energy gained every turn, 100 acts once a turn
+	Loot           map[string]int `yaml:"loot"`            // Drop chances in percent of the items by their names
+	Mover          string         `yaml:"mover"`           // Default movement: still, teleport, diagonal or leap
+	Traits         []string       `yaml:"traits"`          // Special rules, e.g. sure_hit or invisible
+	OnHit          []OnHitConfig  `yaml:"on_hit"`          // Effects of the landed hits on the player
+}
+
+// OnHitConfig defines an effect of the enemy's landed hit on the player.
+type OnHitConfig struct {
+	Effect   string `yaml:"effect"`   // drain_max_health or the name of a status put on the player
+	Chance   int    `yaml:"chance"`   // Chance in percent the effect works on a landed hit [0, 100]
+	Duration int    `yaml:"duration"` // Turns of a status effect
+	Power    [2]int `yaml:"power"`    // Strength of the effect [min, max], e.g. max health drained
+}
+
+// KeyEffects defines the keys of locked doors.
+type KeyEffects struct {
+	Name []string `yaml:"name"` // Key names, the key of lock n gets the name n-1
+}
+
+// VaultConfig defines the extra loot of treasure vaults.
+type VaultConfig struct {
+	ItemsCount [2]int `yaml:"items_count"` // Number of extra items in every vault [min, max]
+}
+
+// LairConfig defines the extra enemies of monster lairs.
+type LairConfig struct {
+	EnemyCount         [2]int `yaml:"enemy_count"`         // Number of extra enemies in every lair [min, max]
+	TreasureMultiplier int    `yaml:"treasure_multiplier"` // Treasure of the lair enemies is multiplied by this value
+}
+
+// ShrineEffects defines the possible blessings of shrines.
+// A blessing raises one of the attributes permanently and fully heals the player.
+type ShrineEffects struct {
+	MaxHealth []int `yaml:"max_health"` // Possible max health bonuses
+	Strength  []int `yaml:"strength"`   // Possible strength bonuses
+	Agility   []int `yaml:"agility"`    // Possible agility bonuses
+}
+
+// TrapEffects defines the effects of traps.
+type TrapEffects struct {
+	ArrowDamage [2]int `yaml:"arrow_damage"` // Damage of an arrow trap [min, max]
+}
+
+// BossConfig defines the boss guarding the exit of the last level.
+// Stats reference the enemy segments like the stats of other enemies; without phases there's no boss.
+type BossConfig struct {
+	Title          string            `yaml:"title"`           // Name shown in the game messages
+	Glyph          string            `yaml:"glyph"`           // Capital letter on the game screen
+	Colour         string            `yaml:"colour"`          // Colour on the game screen
+	EnemyHealth    string            `yaml:"enemy_health"`    // Reference to health segment
+	EnemyAnimosity string            `yaml:"enemy_animosity"` // Reference to animosity segment
+	Treasure       [2]int            `yaml:"treasure"`        // Treasure carried by the boss [min, max]
+	XP             int               `yaml:"xp"`              // Experience for killing the boss
+	Speed          int               `yaml:"speed"`           // Energy gained every turn, 100 acts once a turn
+	Loot           map[string]int    `yaml:"loot"`            // Drop chances in percent of the items by their names
+	Phases         []BossPhaseConfig `yaml:"phases"`          // Phases of the fight, from the first one
+}
+
+// BossPhaseConfig defines a phase of the boss fight.
+type BossPhaseConfig struct {
+	Health        int    `yaml:"health"`         // The phase begins when the boss health falls to this percent of the initial health
+	EnemyStrength string `yaml:"enemy_strength"` // Reference to strength segment
+	EnemyAgility  string `yaml:"enemy_agility"`  // Reference to agility segment
+	Behaviour     string `yaml:"behaviour"`      // How the boss moves: guard, pursue or blink
+	Message       string `yaml:"message"`        // Shown when the phase begins
 }
 
 // WeaponEffects defines the attributes of weapons.
@@ -62,6 +203,8 @@ type WeaponEffects struct {
 	Name     []string `yaml:"name"`     // Possible weapon names
 }
 
-// EnemyNum is a map counting different enemy types.
-// The key is the enemy type name, value is the count.
-type EnemyNum map[string]int
+// ArmorEffects defines the attributes of armors.
+type ArmorEffects struct {
+	Defense [2]int   `yaml:"defense"` // Damage absorbed from every hit [min, max]
+	Name    []string `yaml:"name"`    // Possible armor names
+}
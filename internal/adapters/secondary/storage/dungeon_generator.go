@@ -1,7 +1,7 @@
 package storage
 
 import (
-	"math/rand"
+	"sort"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
@@ -11,78 +11,167 @@ import (
 
 // GenerateDungeonFromConfig creates a complete dungeon level based on configuration.
 // It handles player placement, item generation, enemy spawning, and room assignment.
+// The layout is built by the generator named in the level config, the grid generator is used by default;
+// a level with an ASCII map is built from the map instead.
+// Special rooms get their extras: vaults get more items, lairs get more enemies, shrines get a blessing.
+// Hidden traps are placed on free floor tiles of the rooms.
+// Every level except the first one has stairs up in the start room.
+// The artifact of the campaign lies in an ordinary room of the last level.
+// The exit of the last level is moved into the boss arena and sealed until the boss is defeated;
+// the boss waits in the arena, or in any other room if the arena is full.
+// In the endless mode there's no last level, and the levels past the last level range are extrapolated
+// by the scaling curves of the config.
 // Parameters:
 //   - level: The dungeon level to generate
 //   - cfg: Configuration containing game parameters and probabilities
 //   - player: Optional existing player character (new one created if nil)
+//   - rng: Random generator of the game session
 //
 // Returns a fully populated Dungeon structure ready for gameplay.
-func GenerateDungeonFromConfig(level int, cfg *Config, player *unit.Character) dungeon.Dungeon {
-	lvlCfg := pickLevelConfig(cfg.Levels, level)
-
-	d := dungeon.GenerateDungeon()
-	d.LevelNumber = level
+func GenerateDungeonFromConfig(level int, cfg *Config, player *unit.Character, rng *common.RNG) dungeon.Dungeon {
+	depth := cfg.endlessDepth(level)
+	lvlCfg := cfg.scaleLevel(pickLevelConfig(cfg.Levels, level), depth)
 
 	if player == nil {
 		player = createPlayer(cfg.CharacterStartParams)
+		player.Stats.Endless = cfg.EndlessMode
 	}
-	player.Stats.LevelAchieved = level
+	player.Stats.LevelAchieved = max(player.Stats.LevelAchieved, level)
+	// keys fit the doors of their level only
+	player.Inventory.Keys = nil
+	if lvlCfg.layout != nil {
+		d := generateMapLevel(level, cfg, lvlCfg, player, rng)
+		cfg.scaleEnemies(d.Enemies, depth)
+		return d
+	}
+
+	generator, err := dungeon.GeneratorByName(lvlCfg.Generator)
+	if err != nil {
+		generator = dungeon.GridGenerator{}
+	}
+	d := generator.Generate(rng, dungeon.LayoutOptions{
+		ExtraConnections: lvlCfg.ExtraLinks,
+		DeadEnds:         lvlCfg.DeadEnds,
+	})
+	d.LevelNumber = level
 	d.Player = player
 
-	d.Items = generateItems(cfg, lvlCfg.ItemsCount)
-	d.Enemies = generateEnemies(cfg, lvlCfg.EnemyChances, lvlCfg.EnemyCount, lvlCfg.Treasure)
+	last := cfg.IsLastLevel(level)
+	arena := -1
+	if last && len(cfg.Boss.Phases) > 0 {
+		arena = d.MakeArena(rng)
+	}
+	d.AssignRoomTypes(rng, roomWeights(lvlCfg.RoomWeights))
+	keyRooms := d.LockDoors(rng, lvlCfg.LockedDoors)
+	d.HideSecrets(rng, lvlCfg.SecretDoors, lvlCfg.SecretCorridors)
+	d.SearchChance = lvlCfg.SearchChance
+	d.Items = generateItems(rng, cfg, lvlCfg.ItemsCount, len(keyRooms))
+	d.Enemies = generateEnemies(rng, cfg, lvlCfg.EnemyChances, lvlCfg.EnemyCount, lvlCfg.Treasure)
+
+	// extras of the special rooms are placed in their rooms, keyed by the room index
+	vaultItems := make(map[item.Item]int)
+	lairEnemies := make(map[dungeon.Coordinator]int)
+	lairTreasure := [2]int{lvlCfg.Treasure[0] * cfg.Lair.TreasureMultiplier, lvlCfg.Treasure[1] * cfg.Lair.TreasureMultiplier}
+	for i := range d.Rooms {
+		switch d.Rooms[i].Type {
+		case dungeon.RoomVault:
+			for _, it := range generateItems(rng, cfg, cfg.Vault.ItemsCount, 0) {
+				vaultItems[it] = i
+				d.Items = append(d.Items, it)
+			}
+		case dungeon.RoomLair:
+			for _, e := range generateEnemies(rng, cfg, lvlCfg.EnemyChances, cfg.Lair.EnemyCount, lairTreasure) {
+				lairEnemies[e] = i
+				d.Enemies = append(d.Enemies, e)
+			}
+		case dungeon.RoomShrine:
+			d.Rooms[i].Blessing = createBlessing(rng, cfg.Shrine)
+		}
+	}
 	var startRoom, endRoom *dungeon.Room
 	for i := range d.Rooms {
 		if d.Rooms[i].Type == dungeon.RoomStart {
 			startRoom = &d.Rooms[i]
-		} else if d.Rooms[i].Type == dungeon.RoomEnd {
+		} else if d.Rooms[i].Type == dungeon.RoomEnd || d.Rooms[i].Type == dungeon.RoomArena {
 			endRoom = &d.Rooms[i]
 		}
 	}
 
 	if startRoom != nil {
-		startCoords := getRandomFloorCoord(*startRoom)
+		startCoords := getRandomFloorCoord(rng, *startRoom)
 		d.Player.SetCoords(startCoords)
 		startRoom.Visible = dungeon.FogClean
 	} else {
 		panic("no start room found")
 	}
 
-	occupiedCoords := make(map[common.Coords]bool)
-	for i := range d.Items {
-		var coord common.Coords
-		var attempts int
-		maxAttempts := 100
-
-		for attempts = 0; attempts < maxAttempts; attempts++ {
-			room := getRandomNonSpecialRoom(d.Rooms[:], startRoom, endRoom)
-			coord = getRandomFloorCoord(room)
-			if (!occupiedCoords[coord]) && (endRoom == nil || coord != d.Exit) {
-				break
-			}
+	// nothing is placed on the player or the exit
+	occupiedCoords := map[common.Coords]bool{d.Player.GetCoords(): true, d.Exit: true}
+	if level > 1 {
+		if coord, ok := placeOnFloor(rng, []dungeon.Room{*startRoom}, occupiedCoords); ok {
+			d.StairsUp = &coord
+			occupiedCoords[coord] = true
 		}
-
-		d.Items[i].SetCoords(coord)
+	}
+	if last && cfg.Victory() == dungeon.VictoryRetrieveArtifact {
+		if coord, ok := placeOnFloor(rng, nonSpecialRooms(d.Rooms[:], startRoom, endRoom), occupiedCoords); ok {
+			d.Artifact = &coord
+			occupiedCoords[coord] = true
+		}
+	}
+	items := d.Items[:0]
+	for _, it := range d.Items {
+		rooms := nonSpecialRooms(d.Rooms[:], startRoom, endRoom)
+		if key, ok := it.(*item.Key); ok {
+			rooms = []dungeon.Room{d.Rooms[keyRooms[key.Lock-1]]}
+		} else if vault, ok := vaultItems[it]; ok {
+			rooms = []dungeon.Room{d.Rooms[vault]}
+		}
+		coord, ok := placeOnFloor(rng, rooms, occupiedCoords)
+		if !ok {
+			continue
+		}
+		it.SetCoords(coord)
 		occupiedCoords[coord] = true
+		items = append(items, it)
+	}
+	d.Items = items
+	if !d.Solvable() {
+		unlockAll(&d)
 	}
 
+	enemies := d.Enemies[:0]
 	for _, e := range d.Enemies {
-		var coord common.Coords
-		var attempts int
-		maxAttempts := 100
-		for attempts = 0; attempts < maxAttempts; attempts++ {
-			room := getRandomNonSpecialRoom(d.Rooms[:], startRoom, endRoom)
-			coord = getRandomFloorCoord(room)
-			if endRoom == nil || coord != d.Exit {
-				break
-			}
+		rooms := nonSpecialRooms(d.Rooms[:], startRoom, endRoom)
+		if lair, ok := lairEnemies[e]; ok {
+			rooms = []dungeon.Room{d.Rooms[lair]}
 		}
-		if attempts >= maxAttempts {
-			room := getRandomNonSpecialRoom(d.Rooms[:], startRoom, endRoom)
-			coord = getRandomFloorCoord(room)
+		coord, ok := placeOnFloor(rng, rooms, occupiedCoords)
+		if !ok {
+			continue
 		}
 		e.SetCoords(coord)
+		occupiedCoords[coord] = true
+		enemies = append(enemies, e)
 	}
+	d.Enemies = enemies
+
+	if arena != -1 {
+		boss := createBoss(rng, cfg)
+		coord, ok := placeOnFloor(rng, []dungeon.Room{d.Rooms[arena]}, occupiedCoords)
+		if !ok {
+			coord, ok = placeOnFloor(rng, d.Rooms[:], occupiedCoords)
+		}
+		if !ok {
+			panic("no free floor tile for the boss")
+		}
+		boss.SetCoords(coord)
+		occupiedCoords[coord] = true
+		d.Enemies = append(d.Enemies, boss)
+	}
+
+	d.Traps = generateTraps(rng, cfg, lvlCfg, last, d.Rooms[:], startRoom, endRoom, occupiedCoords, d.Exit)
+	cfg.scaleEnemies(d.Enemies, depth)
 	return d
 }
 
@@ -94,18 +183,16 @@ func createPlayer(params Character) *unit.Character {
 	p.Health = params.Health
 	p.Strength = params.Strength
 	p.Agility = params.Agility
+	p.Speed = params.Speed
+	p.Energy = unit.ActionEnergy
+	p.Level = 1
 	p.Stats.LevelAchieved = 1
 	return p
 }
 
-// generateEnemies creates a set of enemies for the dungeon based on configuration.
-// Parameters:
-//   - cfg: Game configuration containing enemy definitions
-//   - chances: Probability weights for different enemy types
-//   - countRange: Minimum and maximum number of enemies to generate
-//   - treasureRange: Range of treasure values for enemies
-//
-// Returns a slice of enemies implementing the Coordinator interface.
+// pickLevelConfig returns the config of the range covering the current level.
+// Every level of the campaign is covered by a range after the config validation,
+// a level past all ranges gets the last range of the list.
 func pickLevelConfig(levels []Level, current int) Level {
 	for _, lvl := range levels {
 		if current >= lvl.Range[0] && current <= lvl.Range[1] {
@@ -115,89 +202,138 @@ func pickLevelConfig(levels []Level, current int) Level {
 	return levels[len(levels)-1]
 }
 
-// createEnemy constructs a specific enemy instance with randomized attributes.
+// generateEnemies creates a random number of enemies in countRange, choosing every kind by its weight in chances.
 // Parameters:
-//   - cfg: Game configuration containing enemy segments
-//   - etype: Enemy type identifier
-//   - treasureRange: Range for random treasure value
+//   - cfg: Game configuration containing enemy kinds and segments
+//   - chances: Weights of the enemy kinds by their config names
+//   - countRange: Range for random number of enemies
+//   - treasureRange: Range for random treasure value of every enemy
 //
-// Returns an enemy implementing the Coordinator interface.
-func generateEnemies(cfg *Config, chances map[string]int, countRange [2]int, treasureRange [2]int) []dungeon.Coordinator {
-	count := common.RandomInRange(countRange[0], countRange[1])
+// Returns the enemies without positions.
+func generateEnemies(rng *common.RNG, cfg *Config, chances map[string]int, countRange [2]int, treasureRange [2]int) []dungeon.Coordinator {
+	count := common.RandomInRange(rng, countRange[0], countRange[1])
 	enemies := make([]dungeon.Coordinator, 0, count)
 
+	// map order is random, so names are sorted to keep generation reproducible by seed
+	names := make([]string, 0, len(chances))
+	for enemy := range chances {
+		names = append(names, enemy)
+	}
+	sort.Strings(names)
+
 	weights := make([]string, 0)
-	for enemy, chance := range chances {
-		for i := 0; i < chance; i++ {
+	for _, enemy := range names {
+		for i := 0; i < chances[enemy]; i++ {
 			weights = append(weights, enemy)
 		}
 	}
 	for i := 0; i < count; i++ {
-		etype := weights[rand.Intn(len(weights))]
-		enemies = append(enemies, createEnemy(cfg, etype, treasureRange))
+		etype := weights[rng.Intn(len(weights))]
+		enemies = append(enemies, createEnemy(rng, cfg, etype, treasureRange))
 	}
 	return enemies
 }
 
-// createEnemy constructs a specific enemy instance with randomized attributes.
+// createEnemy constructs an enemy of the kind from the config with randomized attributes.
 // Parameters:
-//   - cfg: Game configuration containing enemy segments
-//   - etype: Enemy type identifier
+//   - cfg: Game configuration containing enemy kinds and segments
+//   - name: Config name of the enemy kind
 //   - treasureRange: Range for random treasure value
 //
 // Returns an enemy implementing the Coordinator interface.
-func createEnemy(cfg *Config, etype string, treasureRange [2]int) dungeon.Coordinator {
-	einfo := cfg.Enemies[etype]
+func createEnemy(rng *common.RNG, cfg *Config, name string, treasureRange [2]int) dungeon.Coordinator {
+	einfo := cfg.Enemies[name]
+	kind, err := enemyKind(name, einfo)
+	if err != nil {
+		panic("invalid enemy " + name + ": " + err.Error())
+	}
 
 	agilityRange := cfg.EnemyAgility[einfo.EnemyAgility]
 	strengthRange := cfg.EnemyStrength[einfo.EnemyStrength]
 	animosityRange := cfg.EnemyAnimosity[einfo.EnemyAnimosity]
 	healthRange := cfg.EnemyHealth[einfo.EnemyHealth]
 
-	e := EnemyNum{
-		"zombie":       0,
-		"vampire":      1,
-		"ghost":        2,
-		"ogr":          3,
-		"snake_wizard": 4,
-	}
-	enemyIndex, ok := e[etype]
-	if !ok {
-		panic("unknown enemy type: " + etype)
-	}
-	enemyType := unit.EnemyType(enemyIndex)
-
-	enemy := unit.NewEnemyWithoutCoords(enemyType)
+	enemy := unit.NewEnemy(kind)
 
-	enemy.Agility = common.RandomInRange(agilityRange[0], agilityRange[1])
-	enemy.Strength = common.RandomInRange(strengthRange[0], strengthRange[1])
-	enemy.Animosity = common.RandomInRange(animosityRange[0], animosityRange[1])
-	enemy.Health = common.RandomInRange(healthRange[0], healthRange[1])
-	enemy.Treasure = common.RandomInRange(treasureRange[0], treasureRange[1])
+	enemy.Agility = common.RandomInRange(rng, agilityRange[0], agilityRange[1])
+	enemy.Strength = common.RandomInRange(rng, strengthRange[0], strengthRange[1])
+	enemy.Animosity = common.RandomInRange(rng, animosityRange[0], animosityRange[1])
+	enemy.Health = common.RandomInRange(rng, healthRange[0], healthRange[1])
+	enemy.Treasure = common.RandomInRange(rng, treasureRange[0], treasureRange[1])
+	enemy.XP = einfo.XP
+	enemy.Speed = einfo.Speed
+	enemy.Loot = rollLoot(rng, cfg, einfo.Loot)
 
 	return enemy
 }
 
+// createBoss constructs the boss with randomized attributes of every phase.
+// Phase health thresholds are turned from percents into health points of the boss.
+func createBoss(rng *common.RNG, cfg *Config) *unit.Enemy {
+	healthRange := cfg.EnemyHealth[cfg.Boss.EnemyHealth]
+	animosityRange := cfg.EnemyAnimosity[cfg.Boss.EnemyAnimosity]
+	health := common.RandomInRange(rng, healthRange[0], healthRange[1])
+
+	phases := make([]unit.BossPhase, len(cfg.Boss.Phases))
+	for i, p := range cfg.Boss.Phases {
+		strengthRange := cfg.EnemyStrength[p.EnemyStrength]
+		agilityRange := cfg.EnemyAgility[p.EnemyAgility]
+		behaviour, _ := unit.BossBehaviourByName(p.Behaviour)
+		phases[i] = unit.BossPhase{
+			Health:    health * p.Health / 100,
+			Strength:  common.RandomInRange(rng, strengthRange[0], strengthRange[1]),
+			Agility:   common.RandomInRange(rng, agilityRange[0], agilityRange[1]),
+			Behaviour: behaviour,
+			Message:   p.Message,
+		}
+	}
+	animosity := common.RandomInRange(rng, animosityRange[0], animosityRange[1])
+	treasure := common.RandomInRange(rng, cfg.Boss.Treasure[0], cfg.Boss.Treasure[1])
+	kind, _ := bossKind(cfg.Boss)
+	boss := unit.NewBoss(kind, health, animosity, treasure, cfg.Boss.XP, phases)
+	boss.Speed = cfg.Boss.Speed
+	boss.Loot = rollLoot(rng, cfg, cfg.Boss.Loot)
+	return boss
+}
+
+// unlockAll opens all locked doors and removes their keys.
+// It's the fallback for a level which failed the solvability check.
+func unlockAll(d *dungeon.Dungeon) {
+	for i := len(d.Items) - 1; i >= 0; i-- {
+		if key, ok := d.Items[i].(*item.Key); ok {
+			d.UnlockDoor(key.Lock)
+			d.Items = append(d.Items[:i], d.Items[i+1:]...)
+		}
+	}
+}
+
 // generateItems creates random items to populate the dungeon.
 // Parameters:
 //   - cfg: Game configuration containing item definitions
 //   - countRange: Minimum and maximum number of items to generate
+//   - keys: Number of locked doors, a key is created for each of them
 //
 // Returns a slice of generated items.
-func generateItems(cfg *Config, countRange [2]int) []item.Item {
-	count := common.RandomInRange(countRange[0], countRange[1])
-	items := make([]item.Item, 0, count)
+func generateItems(rng *common.RNG, cfg *Config, countRange [2]int, keys int) []item.Item {
+	count := common.RandomInRange(rng, countRange[0], countRange[1])
+	items := make([]item.Item, 0, count+keys)
+
+	for lock := 1; lock <= keys; lock++ {
+		items = append(items, createKey(cfg.Key, lock))
+	}
 
 	for i := 0; i < count; i++ {
-		switch rand.Intn(4) {
+		switch rng.Intn(5) {
 		case 0:
-			items = append(items, createElixir(cfg.Elixir))
+			items = append(items, createElixir(rng, cfg.Elixir))
 		case 1:
-			items = append(items, createScroll(cfg.Scroll))
+			items = append(items, createScroll(rng, cfg.Scroll))
 		case 2:
-			items = append(items, createFood(cfg.Food))
+			items = append(items, createFood(rng, cfg.Food))
 		case 3:
-			items = append(items, createWeapon(cfg.Weapon))
+			items = append(items, createWeapon(rng, cfg.Weapon))
+		case 4:
+			items = append(items, createArmor(rng, cfg.Armor))
 		}
 	}
 
@@ -205,26 +341,30 @@ func generateItems(cfg *Config, countRange [2]int) []item.Item {
 }
 
 // createElixir generates an elixir item with random properties.
-// The elixir will have one randomly selected beneficial effect.
-func createElixir(e ItemEffects) item.Item {
+// The elixir will have one randomly selected effect: a stat boost, haste or slowness.
+func createElixir(rng *common.RNG, e ItemEffects) item.Item {
 	el := &item.Elixir{}
-	el.Duration = common.RandomInRange(e.Duration[0], e.Duration[1])
+	el.Duration = common.RandomInRange(rng, e.Duration[0], e.Duration[1])
 	el.IsActive = false
-	el.Name = e.Name[rand.Intn(len(e.Name))]
+	el.Name = e.Name[rng.Intn(len(e.Name))]
 
-	switch rand.Intn(3) {
+	switch rng.Intn(5) {
 	case 0:
-		el.Agility = common.RandomInRange(e.Agility[0], e.Agility[1])
+		el.Agility = common.RandomInRange(rng, e.Agility[0], e.Agility[1])
 		el.Strength = 0
 		el.MaxHealth = 0
 	case 1:
 		el.Agility = 0
-		el.Strength = common.RandomInRange(e.Strength[0], e.Strength[1])
+		el.Strength = common.RandomInRange(rng, e.Strength[0], e.Strength[1])
 		el.MaxHealth = 0
 	case 2:
 		el.Agility = 0
 		el.Strength = 0
-		el.MaxHealth = common.RandomInRange(e.MaxHealth[0], e.MaxHealth[1])
+		el.MaxHealth = common.RandomInRange(rng, e.MaxHealth[0], e.MaxHealth[1])
+	case 3:
+		el.Speed = common.RandomInRange(rng, e.Haste[0], e.Haste[1])
+	case 4:
+		el.Speed = -common.RandomInRange(rng, e.Slow[0], e.Slow[1])
 	}
 
 	return el
@@ -232,69 +372,231 @@ func createElixir(e ItemEffects) item.Item {
 
 // createScroll generates a scroll item with random properties.
 // The scroll will have one randomly selected magical effect.
-func createScroll(s ItemEffects) item.Item {
+func createScroll(rng *common.RNG, s ItemEffects) item.Item {
 	sc := &item.Scroll{}
-	sc.Duration = common.RandomInRange(s.Duration[0], s.Duration[1])
+	sc.Duration = common.RandomInRange(rng, s.Duration[0], s.Duration[1])
 	sc.IsActive = false
-	sc.Name = s.Name[rand.Intn(len(s.Name))]
+	sc.Name = s.Name[rng.Intn(len(s.Name))]
 
-	switch rand.Intn(3) {
+	switch rng.Intn(3) {
 	case 0:
-		sc.Agility = common.RandomInRange(s.Agility[0], s.Agility[1])
+		sc.Agility = common.RandomInRange(rng, s.Agility[0], s.Agility[1])
 		sc.Strength = 0
 		sc.MaxHealth = 0
 	case 1:
 		sc.Agility = 0
-		sc.Strength = common.RandomInRange(s.Strength[0], s.Strength[1])
+		sc.Strength = common.RandomInRange(rng, s.Strength[0], s.Strength[1])
 		sc.MaxHealth = 0
 	case 2:
 		sc.Agility = 0
 		sc.Strength = 0
-		sc.MaxHealth = common.RandomInRange(s.MaxHealth[0], s.MaxHealth[1])
+		sc.MaxHealth = common.RandomInRange(rng, s.MaxHealth[0], s.MaxHealth[1])
 	}
 
 	return sc
 }
 
 // createFood generates a food item with random nutritional value.
-func createFood(f FoodEffects) item.Item {
+func createFood(rng *common.RNG, f FoodEffects) item.Item {
 	food := &item.Food{}
-	food.Value = common.RandomInRange(f.Health[0], f.Health[1])
-	food.Name = f.Name[rand.Intn(len(f.Name))]
+	food.Value = common.RandomInRange(rng, f.Health[0], f.Health[1])
+	food.Name = f.Name[rng.Intn(len(f.Name))]
 	return food
 }
 
+// createKey generates the key of the lock, named by the lock number.
+func createKey(k KeyEffects, lock int) item.Item {
+	return &item.Key{
+		Name: k.Name[(lock-1)%len(k.Name)],
+		Lock: lock,
+	}
+}
+
+// generateTraps creates hidden traps of the level on the free floor tiles of the rooms except the start room and shrines.
+// There are no trapdoors on the last level, there's nowhere to fall.
+func generateTraps(rng *common.RNG, cfg *Config, lvlCfg Level, last bool, rooms []dungeon.Room, startRoom, endRoom *dungeon.Room,
+	occupied map[common.Coords]bool, exit common.Coords) []dungeon.Trap {
+	// map order is random, so names are sorted to keep generation reproducible by seed
+	names := make([]string, 0, len(lvlCfg.TrapChances))
+	for name := range lvlCfg.TrapChances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var weights []dungeon.TrapType
+	for _, name := range names {
+		trapType, err := dungeon.TrapTypeByName(name)
+		if err != nil || (last && trapType == dungeon.TrapDoor) {
+			continue
+		}
+		for i := 0; i < lvlCfg.TrapChances[name]; i++ {
+			weights = append(weights, trapType)
+		}
+	}
+	if len(weights) == 0 {
+		return nil
+	}
+
+	count := common.RandomInRange(rng, lvlCfg.Traps[0], lvlCfg.Traps[1])
+	traps := make([]dungeon.Trap, 0, count)
+	for i := 0; i < count; i++ {
+		trap := dungeon.Trap{Type: weights[rng.Intn(len(weights))]}
+		if trap.Type == dungeon.TrapArrow {
+			trap.Damage = common.RandomInRange(rng, cfg.Trap.ArrowDamage[0], cfg.Trap.ArrowDamage[1])
+		}
+		placed := false
+		for attempts := 0; attempts < 100 && !placed; attempts++ {
+			room := getRandomNonSpecialRoom(rng, rooms, startRoom, nil)
+			trap.Coords = getRandomFloorCoord(rng, room)
+			placed = room.Type != dungeon.RoomShrine && !occupied[trap.Coords] && trap.Coords != exit
+		}
+		if placed {
+			occupied[trap.Coords] = true
+			traps = append(traps, trap)
+		}
+	}
+	return traps
+}
+
+// LastLevel returns the number of the last level of the campaign.
+func (c *Config) LastLevel() int {
+	return c.Campaign.Levels
+}
+
+// IsLastLevel checks if the level is the last one of the campaign. The endless mode has no last level.
+func (c *Config) IsLastLevel(level int) bool {
+	return !c.EndlessMode && level == c.Campaign.Levels
+}
+
+// Victory returns the victory condition of the campaign, reaching the exit if it's unknown.
+func (c *Config) Victory() dungeon.Victory {
+	victory, _ := dungeon.VictoryByName(c.Campaign.Victory)
+	return victory
+}
+
+// CombatFormula returns the combat formula of the config.
+func (c *Config) CombatFormula() unit.Combat {
+	return unit.Combat{
+		BaseHitChance:  c.Combat.BaseHitChance,
+		AgilityStep:    c.Combat.AgilityStep,
+		MinHitChance:   c.Combat.MinHitChance,
+		MaxHitChance:   c.Combat.MaxHitChance,
+		DamageVariance: c.Combat.DamageVariance,
+		CritChance:     c.Combat.CritChance,
+		CritMultiplier: c.Combat.CritMultiplier,
+	}
+}
+
+// LevelingRules returns the progression of the player of the config.
+func (c *Config) LevelingRules() unit.Leveling {
+	return unit.Leveling{
+		BaseXP:    c.Leveling.BaseXP,
+		MaxHealth: c.Leveling.MaxHealth,
+		Strength:  c.Leveling.Strength,
+		Agility:   c.Leveling.Agility,
+	}
+}
+
+// createBlessing generates a shrine blessing raising one random attribute.
+func createBlessing(rng *common.RNG, s ShrineEffects) *dungeon.Blessing {
+	blessing := &dungeon.Blessing{}
+	switch rng.Intn(3) {
+	case 0:
+		blessing.MaxHealth = common.RandomInRange(rng, s.MaxHealth[0], s.MaxHealth[1])
+	case 1:
+		blessing.Strength = common.RandomInRange(rng, s.Strength[0], s.Strength[1])
+	case 2:
+		blessing.Agility = common.RandomInRange(rng, s.Agility[0], s.Agility[1])
+	}
+	return blessing
+}
+
+// roomWeights converts the room type weights of the level config to room types.
+// Unknown names are rejected when the config is loaded.
+func roomWeights(weights map[string]int) map[dungeon.RoomType]int {
+	result := make(map[dungeon.RoomType]int, len(weights))
+	for name, weight := range weights {
+		if roomType, err := dungeon.RoomTypeByName(name); err == nil {
+			result[roomType] += weight
+		}
+	}
+	return result
+}
+
 // createWeapon generates a weapon with random damage properties.
-func createWeapon(w WeaponEffects) item.Item {
+func createWeapon(rng *common.RNG, w WeaponEffects) item.Item {
 	weapon := &item.Weapon{
-		Name:     w.Name[rand.Intn(len(w.Name))],
-		Strength: common.RandomInRange(w.Strength[0], w.Strength[1]),
+		Name:     w.Name[rng.Intn(len(w.Name))],
+		Strength: common.RandomInRange(rng, w.Strength[0], w.Strength[1]),
 	}
 	return weapon
 }
 
+// createArmor generates an armor with random defense.
+func createArmor(rng *common.RNG, a ArmorEffects) item.Item {
+	return &item.Armor{
+		Name:    a.Name[rng.Intn(len(a.Name))],
+		Defense: common.RandomInRange(rng, a.Defense[0], a.Defense[1]),
+	}
+}
+
 // getRandomFloorCoord returns a random walkable position within a room.
 // The position will be within the room's boundaries excluding walls.
-func getRandomFloorCoord(room dungeon.Room) common.Coords {
+func getRandomFloorCoord(rng *common.RNG, room dungeon.Room) common.Coords {
 	minX := room.X + 1
 	maxX := room.X + room.Width - 2
 	minY := room.Y + 1
 	maxY := room.Y + room.Height - 2
 
-	x := common.RandomInRange(minX, maxX)
-	y := common.RandomInRange(minY, maxY)
+	x := common.RandomInRange(rng, minX, maxX)
+	y := common.RandomInRange(rng, minY, maxY)
 
 	return common.Coords{X: x, Y: y}
 }
 
-// getRandomNonSpecialRoom selects a random room that isn't the start or end room.
-// Falls back to any non-start room if no other options exist.
-// Panics if no suitable rooms are available.
-func getRandomNonSpecialRoom(rooms []dungeon.Room, startRoom, endRoom *dungeon.Room) dungeon.Room {
+// maxPlacementAttempts is the number of random tiles tried before looking through all free tiles
+const maxPlacementAttempts = 100
+
+// placeOnFloor returns a free floor tile in one of the rooms. Random tiles of random rooms are tried first,
+// then a random one of all free tiles is taken, so a crowded level never gets two entities on one tile.
+// Returns false if all the rooms are full.
+func placeOnFloor(rng *common.RNG, rooms []dungeon.Room, occupied map[common.Coords]bool) (common.Coords, bool) {
+	for attempts := 0; attempts < maxPlacementAttempts; attempts++ {
+		coord := getRandomFloorCoord(rng, rooms[rng.Intn(len(rooms))])
+		if !occupied[coord] {
+			return coord, true
+		}
+	}
+	var free []common.Coords
+	for _, room := range rooms {
+		for y := room.Y + 1; y < room.Y+room.Height-1; y++ {
+			for x := room.X + 1; x < room.X+room.Width-1; x++ {
+				if c := (common.Coords{X: x, Y: y}); !occupied[c] {
+					free = append(free, c)
+				}
+			}
+		}
+	}
+	if len(free) == 0 {
+		return common.Coords{}, false
+	}
+	return free[rng.Intn(len(free))], true
+}
+
+// getRandomNonSpecialRoom selects a random room among the candidates of nonSpecialRooms.
+func getRandomNonSpecialRoom(rng *common.RNG, rooms []dungeon.Room, startRoom, endRoom *dungeon.Room) dungeon.Room {
+	candidates := nonSpecialRooms(rooms, startRoom, endRoom)
+	return candidates[rng.Intn(len(candidates))]
+}
+
+// nonSpecialRooms returns the rooms that aren't the start or end room or a shrine.
+// If there are none, it returns every room but the start one, the end room and shrines included.
+// Panics if the start room is the only room.
+func nonSpecialRooms(rooms []dungeon.Room, startRoom, endRoom *dungeon.Room) []dungeon.Room {
 	var candidates []dungeon.Room
 	for _, room := range rooms {
 		if (startRoom == nil || room.Coords != startRoom.Coords) &&
-			(endRoom == nil || room.Coords != endRoom.Coords) {
+			(endRoom == nil || room.Coords != endRoom.Coords) && room.Type != dungeon.RoomShrine {
 			candidates = append(candidates, room)
 		}
 	}
@@ -308,5 +610,5 @@ func getRandomNonSpecialRoom(rooms []dungeon.Room, startRoom, endRoom *dungeon.R
 			panic("no suitable rooms available")
 		}
 	}
-	return candidates[rand.Intn(len(candidates))]
+	return candidates
 }
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+func TestGeneratedLevelsAreValid(t *testing.T) {
+	const seeds, endlessLevels = 20, 10
+	cfg := loadTestConfig(t)
+	cfg.EndlessMode = true
+	for seed := int64(1); seed <= seeds; seed++ {
+		rng := common.NewRNG(seed)
+		for level := 1; level <= cfg.LastLevel()+endlessLevels; level++ {
+			d := GenerateDungeonFromConfig(level, cfg, nil, rng)
+			if err := d.Validate(); err != nil {
+				t.Errorf("seed %d level %d: %v", seed, level, err)
+			}
+		}
+	}
+}
+
+func TestGenerateDungeonFromConfigIsDeterministic(t *testing.T) {
+	cfg := loadTestConfig(t)
+	for _, level := range []int{1, cfg.LastLevel()} {
+		want, _ := json.Marshal(DungeonToDTO(GenerateDungeonFromConfig(level, cfg, nil, common.NewRNG(99))))
+		got, _ := json.Marshal(DungeonToDTO(GenerateDungeonFromConfig(level, cfg, nil, common.NewRNG(99))))
+		if !bytes.Equal(got, want) {
+			t.Errorf("level %d differs between two generations with one seed", level)
+		}
+	}
+}
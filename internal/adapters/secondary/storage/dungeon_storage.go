@@ -2,32 +2,64 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 )
 
 const (
-	// GameStateFile defines the path where game state is saved
-	GameStateFile = "internal/adapters/secondary/storage/dungeon_save.json"
+	// SavesDir defines the directory where save slots are stored, one JSON file per slot
+	SavesDir = "internal/adapters/secondary/storage/saves"
+
+	// LegacySaveFile defines the path of the single save file used before save slots,
+	// it's moved into the first unused slot once
+	LegacySaveFile = "internal/adapters/secondary/storage/dungeon_save.json"
 
 	// LeaderboardFile defines the path where leaderboard data is stored
 	LeaderboardFile = "internal/adapters/secondary/storage/leaderboard.json"
 
 	// MaxLeaderboardLen specifies the maximum number of entries kept in the leaderboard
 	MaxLeaderboardLen = 30
+
+	// MaxSaveSlots specifies the maximum number of save slots
+	MaxSaveSlots = 9
+
+	// saveFileExt is the extension of save slot files
+	saveFileExt = ".json"
+)
+
+var (
+	// ErrInvalidSlotName is returned when a slot name can't be used as a save file name.
+	ErrInvalidSlotName = errors.New("invalid save slot name")
+
+	// ErrNoFreeSlot is returned when all MaxSaveSlots are taken.
+	ErrNoFreeSlot = errors.New("all save slots are taken")
 )
 
+// slotNamePattern restricts slot names to characters which are safe in file names.
+var slotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // DungeonStorage defines the interface for persistent game data operations.
-// It handles saving/loading game state and managing leaderboard entries.
+// It handles saving/loading game state in named slots and managing leaderboard entries.
 type DungeonStorage interface {
-	// SaveGameState persists the current dungeon state to storage
-	SaveGameState(data DungeonData) error
+	// SaveGameState persists the current dungeon state to the named slot
+	SaveGameState(slot string, data DungeonData) error
+
+	// LoadGameState retrieves the saved game state from the named slot
+	LoadGameState(slot string) (*DungeonData, error)
 
-	// LoadGameState retrieves the saved game state from storage
-	LoadGameState() (*DungeonData, error)
+	// ListSaveSlots returns the summaries of all existing slots, most recent first
+	ListSaveSlots() ([]common.SaveSlotInfo, error)
+
+	// DeleteSave removes the named slot from storage
+	DeleteSave(slot string) error
 
 	// SaveLeaderboard adds a new entry to the leaderboard and maintains sorting
 	SaveLeaderboard(entry common.Stats) error
@@ -44,35 +76,227 @@ func NewJSONDungeonStorage() *JSONDungeonStorage {
 	return &JSONDungeonStorage{}
 }
 
-// SaveGameState serializes the dungeon data to JSON and writes it to GameStateFile.
-// Returns an error if marshaling or file operations fail.
-func (s *JSONDungeonStorage) SaveGameState(data DungeonData) error {
+// SlotName returns the default name of the slot with the given number (1..MaxSaveSlots).
+func SlotName(num int) string {
+	return fmt.Sprintf("slot_%d", num)
+}
+
+// slotPath returns the save file path for the slot or ErrInvalidSlotName.
+func slotPath(slot string) (string, error) {
+	if !slotNamePattern.MatchString(slot) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSlotName, slot)
+	}
+	return filepath.Join(SavesDir, slot+saveFileExt), nil
+}
+
+// SaveGameState fills the slot metadata, serializes the dungeon data to JSON
+// and atomically writes it to the slot file in SavesDir.
+// The previous SaveBackups versions of the slot are kept as backups.
+// Returns an error if the slot name is invalid, marshaling or file operations fail.
+func (s *JSONDungeonStorage) SaveGameState(slot string, data DungeonData) error {
+	path, err := slotPath(slot)
+	if err != nil {
+		return err
+	}
+	data.Version = CurrentSaveVersion
+	data.Meta = SlotMetaData{
+		Name:      slot,
+		Level:     data.LevelNumber,
+		Treasure:  data.Player.Stats.TreasuresReceived,
+		Health:    data.Player.Unit.Health,
+		MaxHealth: data.Player.MaxHealth,
+		Seed:      data.Seed,
+		SavedAt:   time.Now(),
+	}
 	dataBytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(GameStateFile, dataBytes, 0o644)
+	if err := os.MkdirAll(SavesDir, 0o755); err != nil {
+		return err
+	}
+	return writeFileWithBackups(path, dataBytes, 0o644)
+}
+
+// LoadGameState reads the game state from the slot file, upgrades it to CurrentSaveVersion
+// and deserializes it.
+// If the slot file is missing or can't be parsed, the newest valid backup is loaded instead.
+// Returns a pointer to DungeonData or the error of the slot file if no backup is valid.
+// Returns *SaveVersionError if the save was written by a newer version of the game,
+// the backups aren't tried then because they are older versions of the same run.
+func (s *JSONDungeonStorage) LoadGameState(slot string) (*DungeonData, error) {
+	path, err := slotPath(slot)
+	if err != nil {
+		return nil, err
+	}
+	dungeonData, loadErr := loadSaveFile(path)
+	if loadErr == nil {
+		return dungeonData, nil
+	}
+	var versionErr *SaveVersionError
+	if errors.As(loadErr, &versionErr) {
+		return nil, loadErr
+	}
+	for n := 1; n <= SaveBackups; n++ {
+		if dungeonData, err := loadSaveFile(backupPath(path, n)); err == nil {
+			return dungeonData, nil
+		}
+	}
+	return nil, loadErr
 }
 
-// LoadGameState reads the game state from GameStateFile and deserializes it.
-// Returns a pointer to DungeonData or error if file doesn't exist or is invalid.
-func (s *JSONDungeonStorage) LoadGameState() (*DungeonData, error) {
-	data, err := os.ReadFile(GameStateFile)
+// loadSaveFile reads and decodes one save file.
+func loadSaveFile(path string) (*DungeonData, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return decodeSave(data)
+}
+
+// saveHeader is the part of a save file read to list the slots without decoding the whole game.
+type saveHeader struct {
+	Version int           `json:"version"`
+	Meta    *SlotMetaData `json:"meta"`
+}
+
+// loadSlotMeta reads the metadata of the slot, using the backups if the slot file is broken like LoadGameState.
+func loadSlotMeta(slot string) (SlotMetaData, error) {
+	path, err := slotPath(slot)
+	if err != nil {
+		return SlotMetaData{}, err
+	}
+	meta, loadErr := readSaveMeta(path)
+	if loadErr == nil {
+		return meta, nil
+	}
+	for n := 1; n <= SaveBackups; n++ {
+		if meta, err := readSaveMeta(backupPath(path, n)); err == nil {
+			return meta, nil
+		}
+	}
+	return SlotMetaData{}, loadErr
+}
+
+// readSaveMeta reads the metadata of one save file. Saves written before the slots have no metadata,
+// so they are decoded and migrated completely.
+func readSaveMeta(path string) (SlotMetaData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SlotMetaData{}, err
+	}
+	var header saveHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return SlotMetaData{}, err
+	}
+	if header.Meta != nil {
+		return *header.Meta, nil
+	}
+	dungeonData, err := decodeSave(data)
+	if err != nil {
+		return SlotMetaData{}, err
+	}
+	return dungeonData.Meta, nil
+}
 
-	var dungeonData DungeonData
-	if err := json.Unmarshal(data, &dungeonData); err != nil {
+// ImportLegacySave moves the save file of the game versions without slots into the first unused slot,
+// so it's listed and migrated like any other slot. Does nothing if there's no legacy save.
+// It's called once when the game starts.
+func ImportLegacySave() error {
+	if _, err := os.Stat(LegacySaveFile); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for i := 1; i <= MaxSaveSlots; i++ {
+		path, err := slotPath(SlotName(i))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			continue
+		}
+		if err := os.MkdirAll(SavesDir, 0o755); err != nil {
+			return err
+		}
+		return os.Rename(LegacySaveFile, path)
+	}
+	return nil
+}
+
+// ListSaveSlots reads the metadata of every slot in SavesDir, using backups for broken slot files.
+// Only the metadata is read, the games aren't decoded.
+// Slots which can't be read at all are skipped. Returns empty slice if there are no saves yet.
+// The result is sorted by saving time, most recent first.
+func (s *JSONDungeonStorage) ListSaveSlots() ([]common.SaveSlotInfo, error) {
+	entries, err := os.ReadDir(SavesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []common.SaveSlotInfo{}, nil
+		}
 		return nil, err
 	}
 
-	return &dungeonData, nil
+	slots := make([]common.SaveSlotInfo, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		// slot files and their backups, but not temporary files
+		name, _, found := strings.Cut(entry.Name(), saveFileExt)
+		if entry.IsDir() || !found || strings.HasPrefix(name, ".") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		meta, err := loadSlotMeta(name)
+		if err != nil {
+			continue
+		}
+		info := DTOToSlotInfo(meta)
+		info.Name = name
+		slots = append(slots, info)
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].SavedAt.After(slots[j].SavedAt)
+	})
+	return slots, nil
+}
+
+// DeleteSave deletes the slot file with its backups. Deleting a missing slot is not an error.
+func (s *JSONDungeonStorage) DeleteSave(slot string) error {
+	path, err := slotPath(slot)
+	if err != nil {
+		return err
+	}
+	if err := removeWithBackups(path); err != nil {
+		return fmt.Errorf("failed to delete save file: %w", err)
+	}
+	return nil
+}
+
+// FreeSlot returns the name of the first unused slot for a new game.
+// Returns ErrNoFreeSlot if all MaxSaveSlots are taken, a slot is never overwritten silently.
+func (s *JSONDungeonStorage) FreeSlot() (string, error) {
+	slots, err := s.ListSaveSlots()
+	if err != nil {
+		return "", err
+	}
+	used := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		used[slot.Name] = true
+	}
+	for i := 1; i <= MaxSaveSlots; i++ {
+		if !used[SlotName(i)] {
+			return SlotName(i), nil
+		}
+	}
+	return "", ErrNoFreeSlot
 }
 
 // SaveLeaderboard adds a new entry to the leaderboard while maintaining:
 // - Sorting by treasures received (descending)
-// - Maximum length of MaxLeaderboardLen
+// - Maximum length of MaxLeaderboardLen for campaign and endless runs each
+// The file is replaced atomically, so a failed write keeps the old leaderboard.
 // Returns error if file operations fail.
 func (s *JSONDungeonStorage) SaveLeaderboard(entry common.Stats) error {
 	entries, err := s.GetLeaderboard()
@@ -86,15 +310,21 @@ func (s *JSONDungeonStorage) SaveLeaderboard(entry common.Stats) error {
 		return entries[i].TreasuresReceived > entries[j].TreasuresReceived
 	})
 
-	if len(entries) > MaxLeaderboardLen {
-		entries = entries[:MaxLeaderboardLen]
+	counts := map[bool]int{}
+	kept := entries[:0]
+	for _, e := range entries {
+		if counts[e.Endless] < MaxLeaderboardLen {
+			counts[e.Endless]++
+			kept = append(kept, e)
+		}
 	}
+	entries = kept
 
 	dataBytes, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(LeaderboardFile, dataBytes, 0o644)
+	return writeFileAtomic(LeaderboardFile, dataBytes, 0o644)
 }
 
 // GetLeaderboard retrieves the current leaderboard entries from storage.
@@ -114,31 +344,29 @@ func (s *JSONDungeonStorage) GetLeaderboard() ([]common.Stats, error) {
 	return entries, nil
 }
 
-// DeleteSave deletes current saving in *.json file
-func (s *JSONDungeonStorage) DeleteSave() error {
-	if err := os.Remove(GameStateFile); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete save file: %w", err)
-	}
-	return nil
-}
-
-// GetLeaderboardSlice returns slice of leaderboard stats from storage, sorted descending.
-func GetLeaderboardSlice() []common.Stats {
+// GetLeaderboardSlice returns slice of leaderboard stats of campaign or endless runs from storage, sorted descending.
+func GetLeaderboardSlice(endless bool) []common.Stats {
 	stats, err := NewJSONDungeonStorage().GetLeaderboard()
 
 	if err == nil {
-		sort.Slice(stats, func(i, j int) bool {
-			return stats[i].TreasuresReceived > stats[j].TreasuresReceived
+		category := stats[:0]
+		for _, s := range stats {
+			if s.Endless == endless {
+				category = append(category, s)
+			}
+		}
+		sort.Slice(category, func(i, j int) bool {
+			return category[i].TreasuresReceived > category[j].TreasuresReceived
 		})
-		return stats
+		return category
 	}
 	return nil
 }
 
-// RemoveLastRecord removes the last entry from the leaderboard file.
-// It reads the current leaderboard, removes the last record, and writes the updated list back to the file.
+// RemoveLastRecord removes the last entry of campaign or endless runs from the leaderboard file.
+// It reads the current leaderboard, removes the last record of the category, and writes the updated list back to the file.
 // Returns an error if reading, unmarshaling, or writing the file fails.
-func RemoveLastRecord() error {
+func RemoveLastRecord(endless bool) error {
 	data, err := os.ReadFile(LeaderboardFile)
 	if err != nil {
 		return err
@@ -149,8 +377,11 @@ func RemoveLastRecord() error {
 		return err
 	}
 
-	if len(stats) > 0 {
-		stats = stats[:len(stats)-1]
+	for i := len(stats) - 1; i >= 0; i-- {
+		if stats[i].Endless == endless {
+			stats = append(stats[:i], stats[i+1:]...)
+			break
+		}
 	}
 
 	output, err := json.MarshalIndent(stats, "", "  ")
@@ -158,7 +389,7 @@ func RemoveLastRecord() error {
 		return err
 	}
 
-	err = os.WriteFile(LeaderboardFile, output, 0o644)
+	err = writeFileAtomic(LeaderboardFile, output, 0o644)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListSaveSlotsReadsOnlyMeta(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	saveLevel(t, s, "slot_1", 2)
+	saveLevel(t, s, "slot_2", 5)
+	// the game of a listed slot isn't decoded, broken files without metadata are skipped
+	files := map[string]string{
+		"slot_3.json": `{"version": 2, "meta": {"name": "slot_3", "level": 7}, "rooms": "broken"}`,
+		"slot_4.json": `garbage`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(SavesDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	slots, err := s.ListSaveSlots()
+	if err != nil {
+		t.Fatalf("ListSaveSlots: %v", err)
+	}
+	levels := make(map[string]int)
+	for _, slot := range slots {
+		levels[slot.Name] = slot.Level
+	}
+	want := map[string]int{"slot_1": 2, "slot_2": 5, "slot_3": 7}
+	if len(levels) != len(want) {
+		t.Errorf("listed slots %v, want %v", levels, want)
+	}
+	for name, level := range want {
+		if levels[name] != level {
+			t.Errorf("slot %s has level %d, want %d", name, levels[name], level)
+		}
+	}
+	if slots[0].Name != "slot_2" || slots[1].Name != "slot_1" {
+		t.Errorf("slots %s, %s aren't sorted by saving time", slots[0].Name, slots[1].Name)
+	}
+}
+
+func TestImportLegacySave(t *testing.T) {
+	loadTestConfig(t)
+	legacy, err := os.ReadFile("testdata/save_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	saveLevel(t, s, "slot_1", 1)
+	if err := os.WriteFile(LegacySaveFile, legacy, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ImportLegacySave(); err != nil {
+		t.Fatalf("ImportLegacySave: %v", err)
+	}
+	if _, err := os.Stat(LegacySaveFile); !os.IsNotExist(err) {
+		t.Errorf("legacy save is left in place")
+	}
+	data, err := s.LoadGameState("slot_2")
+	if err != nil {
+		t.Fatalf("load imported slot: %v", err)
+	}
+	if data.LevelNumber != 3 {
+		t.Errorf("imported save has level %d, want 3", data.LevelNumber)
+	}
+	if err := ImportLegacySave(); err != nil {
+		t.Errorf("second ImportLegacySave: %v", err)
+	}
+	if slot, err := s.FreeSlot(); err != nil || slot != "slot_3" {
+		t.Errorf("FreeSlot() = %q, %v, want slot_3", slot, err)
+	}
+}
+
+func TestInvalidSlotName(t *testing.T) {
+	chdirTemp(t)
+	s := NewJSONDungeonStorage()
+	for _, slot := range []string{"", "../slot", "slot 1", "slot.json"} {
+		if err := s.SaveGameState(slot, DungeonData{}); !errors.Is(err, ErrInvalidSlotName) {
+			t.Errorf("SaveGameState(%q) = %v, want ErrInvalidSlotName", slot, err)
+		}
+		if _, err := s.LoadGameState(slot); !errors.Is(err, ErrInvalidSlotName) {
+			t.Errorf("LoadGameState(%q) = %v, want ErrInvalidSlotName", slot, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// ErrUnknownCurve is returned when there's no scaling curve with the requested name.
+var ErrUnknownCurve = errors.New("unknown scaling curve")
+
+// scalingCurves contains the growth factors of the curves by their config names.
+// A factor is 1 at the last level range and grows with the depth past it.
+var scalingCurves = map[string]func(rate float64, depth int) float64{
+	"linear": func(rate float64, depth int) float64 {
+		return 1 + rate*float64(depth)
+	},
+	"exponential": func(rate float64, depth int) float64 {
+		return math.Pow(1+rate, float64(depth))
+	},
+	"logarithmic": func(rate float64, depth int) float64 {
+		return 1 + rate*math.Log1p(float64(depth))
+	},
+}
+
+// scalingCurveNames returns sorted names of all scaling curves.
+func scalingCurveNames() []string {
+	names := make([]string, 0, len(scalingCurves))
+	for name := range scalingCurves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate checks the curve name and rate. An empty curve means no growth.
+func (c ScalingCurve) validate() error {
+	if c.Curve == "" {
+		return nil
+	}
+	if _, ok := scalingCurves[c.Curve]; !ok {
+		return fmt.Errorf("%w: %q, known curves: %v", ErrUnknownCurve, c.Curve, scalingCurveNames())
+	}
+	if c.Rate < 0 {
+		return fmt.Errorf("rate of curve %s must not be negative, got %v", c.Curve, c.Rate)
+	}
+	return nil
+}
+
+// factor returns the multiplier of a value at the depth past the last level range.
+func (c ScalingCurve) factor(depth int) float64 {
+	curve, ok := scalingCurves[c.Curve]
+	if !ok || depth <= 0 {
+		return 1
+	}
+	return curve(c.Rate, depth)
+}
+
+// scaleRange multiplies both ends of the [min, max] range by the factor.
+func scaleRange(r [2]int, factor float64) [2]int {
+	return [2]int{scaleValue(r[0], factor), scaleValue(r[1], factor)}
+}
+
+// scaleValue multiplies the value by the factor, rounding to the nearest integer.
+func scaleValue(v int, factor float64) int {
+	return int(math.Round(float64(v) * factor))
+}
+
+// endlessDepth returns how many levels past the last level range the level is, 0 outside the endless mode.
+func (c *Config) endlessDepth(level int) int {
+	if !c.EndlessMode {
+		return 0
+	}
+	return max(0, level-lastRange(c.Levels))
+}
+
+// lastRange returns the last level covered by the level ranges.
+func lastRange(levels []Level) int {
+	last := 0
+	for _, lvl := range levels {
+		last = max(last, lvl.Range[1])
+	}
+	return last
+}
+
+// scaleLevel extrapolates the enemy count and treasure of the level config to the depth by the endless curves.
+func (c *Config) scaleLevel(lvl Level, depth int) Level {
+	lvl.EnemyCount = scaleRange(lvl.EnemyCount, c.Endless.EnemyCount.factor(depth))
+	lvl.Treasure = scaleRange(lvl.Treasure, c.Endless.Treasure.factor(depth))
+	return lvl
+}
+
+// scaleEnemies raises the health, strength, agility and experience of the enemies to the depth by the endless curve.
+func (c *Config) scaleEnemies(enemies []dungeon.Coordinator, depth int) {
+	factor := c.Endless.EnemyStats.factor(depth)
+	for _, coord := range enemies {
+		enemy, ok := coord.(*unit.Enemy)
+		if !ok {
+			continue
+		}
+		enemy.Health = scaleValue(enemy.Health, factor)
+		enemy.Strength = scaleValue(enemy.Strength, factor)
+		enemy.Agility = scaleValue(enemy.Agility, factor)
+		enemy.XP = scaleValue(enemy.XP, factor)
+	}
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// enemyKind builds the kind of the enemy from its config. Returns an error if the config has unknown names.
+func enemyKind(name string, e Enemy) (unit.EnemyKind, error) {
+	glyph, err := parseGlyph(e.Glyph)
+	if err != nil {
+		return unit.EnemyKind{}, err
+	}
+	colour, err := common.ColourByName(e.Colour)
+	if err != nil {
+		return unit.EnemyKind{}, fmt.Errorf("%w, known colours: %v", err, common.ColourNames())
+	}
+	if _, err := unit.EnemyMoverByName(e.Mover); err != nil {
+		return unit.EnemyKind{}, fmt.Errorf("%w, known movements: %v", err, unit.EnemyMoverNames())
+	}
+	kind := unit.EnemyKind{Name: name, Title: e.Title, Glyph: glyph, Colour: colour, Mover: e.Mover}
+	for _, traitName := range e.Traits {
+		trait, err := unit.TraitByName(traitName)
+		if err != nil {
+			return unit.EnemyKind{}, fmt.Errorf("%w, known traits: %v", err, unit.TraitNames())
+		}
+		kind.Traits = append(kind.Traits, trait)
+	}
+	for _, hit := range e.OnHit {
+		if _, err := unit.OnHitEffectByName(hit.Effect); err != nil {
+			return unit.EnemyKind{}, fmt.Errorf("%w, known effects: %v", err, unit.OnHitEffectNames())
+		}
+		if hit.Chance < 0 || hit.Chance > 100 {
+			return unit.EnemyKind{}, fmt.Errorf("on_hit %s: chance must be in [0, 100], got %d", hit.Effect, hit.Chance)
+		}
+		if hit.Duration < 0 || !validRange(hit.Power) {
+			return unit.EnemyKind{}, fmt.Errorf("on_hit %s: invalid duration %d or power %v", hit.Effect, hit.Duration, hit.Power)
+		}
+		kind.OnHit = append(kind.OnHit, unit.OnHit(hit))
+	}
+	return kind, nil
+}
+
+// bossKind builds the kind of the boss from the boss config. The boss moves by its phases and has no traits.
+func bossKind(b BossConfig) (unit.EnemyKind, error) {
+	glyph, err := parseGlyph(b.Glyph)
+	if err != nil {
+		return unit.EnemyKind{}, err
+	}
+	colour, err := common.ColourByName(b.Colour)
+	if err != nil {
+		return unit.EnemyKind{}, fmt.Errorf("%w, known colours: %v", err, common.ColourNames())
+	}
+	return unit.EnemyKind{Name: "boss", Title: b.Title, Glyph: glyph, Colour: colour}, nil
+}
+
+// parseGlyph checks that the glyph is a capital letter which isn't the exit of ASCII maps.
+func parseGlyph(glyph string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(glyph)
+	if size != len(glyph) || r < 'A' || r > 'Z' || r == dungeon.MapExit {
+		return 0, fmt.Errorf("glyph must be a capital letter other than %q, got %q", dungeon.MapExit, glyph)
+	}
+	return r, nil
+}
+
+// validateEnemies checks the kinds of the enemies and the boss, their segments and that every glyph is unique.
+func validateEnemies(cfg *Config) error {
+	names := make([]string, 0, len(cfg.Enemies))
+	for name := range cfg.Enemies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	glyphs := make(map[rune]string)
+	for _, name := range names {
+		e := cfg.Enemies[name]
+		if e.Title == "" {
+			return fmt.Errorf("%s: no title", name)
+		}
+		kind, err := enemyKind(name, e)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if other, ok := glyphs[kind.Glyph]; ok {
+			return fmt.Errorf("%s: glyph %q is taken by %s", name, kind.Glyph, other)
+		}
+		glyphs[kind.Glyph] = name
+		if _, ok := cfg.EnemyAgility[e.EnemyAgility]; !ok {
+			return fmt.Errorf("%s: unknown enemy_agility segment %q", name, e.EnemyAgility)
+		}
+		if _, ok := cfg.EnemyStrength[e.EnemyStrength]; !ok {
+			return fmt.Errorf("%s: unknown enemy_strength segment %q", name, e.EnemyStrength)
+		}
+		if _, ok := cfg.EnemyAnimosity[e.EnemyAnimosity]; !ok {
+			return fmt.Errorf("%s: unknown enemy_animosity segment %q", name, e.EnemyAnimosity)
+		}
+		if _, ok := cfg.EnemyHealth[e.EnemyHealth]; !ok {
+			return fmt.Errorf("%s: unknown enemy_health segment %q", name, e.EnemyHealth)
+		}
+		if e.XP < 0 {
+			return fmt.Errorf("%s: xp must not be negative, got %d", name, e.XP)
+		}
+		if e.Speed < unit.MinSpeed {
+			return fmt.Errorf("%s: speed must be at least %d, got %d", name, unit.MinSpeed, e.Speed)
+		}
+		if err := validateLoot(e.Loot); err != nil {
+			return fmt.Errorf("%s: loot: %w", name, err)
+		}
+	}
+	if len(cfg.Boss.Phases) > 0 {
+		kind, err := bossKind(cfg.Boss)
+		if err != nil {
+			return fmt.Errorf("boss: %w", err)
+		}
+		if other, ok := glyphs[kind.Glyph]; ok {
+			return fmt.Errorf("boss: glyph %q is taken by %s", kind.Glyph, other)
+		}
+	}
+	return nil
+}
+
+// enemyByGlyph returns the config name of the enemy drawn with the glyph.
+func (c *Config) enemyByGlyph(glyph rune) (string, bool) {
+	for name, e := range c.Enemies {
+		if e.Glyph == string(glyph) {
+			return name, true
+		}
+	}
+	return "", false
+}
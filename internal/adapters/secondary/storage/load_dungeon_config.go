@@ -1,20 +1,28 @@
 package storage
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 	"gopkg.in/yaml.v3"
 )
 
+// DungeonConfigFile defines the path of the dungeon config of the game
+const DungeonConfigFile = "configs/dungeon_config.yaml"
+
 // LoadDungeonConfig reads and parses a YAML configuration file for dungeon generation.
 // The function loads the file from the specified path and unmarshals it into a Config struct.
+// ASCII maps of hand-authored levels are loaded and checked as well.
 //
 // Parameters:
 //   - path: string - filesystem path to the YAML configuration file
 //
 // Returns:
 //   - *Config: pointer to the parsed configuration structure
-//   - error: any error that occurred during file reading or YAML parsing
+//   - error: any error that occurred during file reading, YAML parsing or validation
 //
 // Example usage:
 //
@@ -28,6 +36,243 @@ func LoadDungeonConfig(path string) (*Config, error) {
 		return nil, err
 	}
 	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
-	return &cfg, err
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return &cfg, err
+	}
+	if err := validateDungeonConfig(&cfg); err != nil {
+		return &cfg, err
+	}
+	return &cfg, loadLevelMaps(&cfg, filepath.Dir(path))
+}
+
+// validateDungeonConfig checks the values which can't be checked by YAML parsing.
+func validateDungeonConfig(cfg *Config) error {
+	if err := validateCampaignConfig(cfg); err != nil {
+		return fmt.Errorf("campaign: %w", err)
+	}
+	for _, lvl := range cfg.Levels {
+		if _, err := dungeon.GeneratorByName(lvl.Generator); err != nil {
+			return fmt.Errorf("levels %d-%d: %w, known generators: %v",
+				lvl.Range[0], lvl.Range[1], err, dungeon.GeneratorNames())
+		}
+		for name := range lvl.EnemyChances {
+			if _, ok := cfg.Enemies[name]; !ok {
+				return fmt.Errorf("levels %d-%d: enemy_chances: unknown enemy %q", lvl.Range[0], lvl.Range[1], name)
+			}
+		}
+		if lvl.ExtraLinks < 0 || lvl.ExtraLinks > 1 {
+			return fmt.Errorf("levels %d-%d: extra_connections must be in [0, 1], got %v",
+				lvl.Range[0], lvl.Range[1], lvl.ExtraLinks)
+		}
+		if lvl.DeadEnds < 0 {
+			return fmt.Errorf("levels %d-%d: dead_ends must not be negative, got %d",
+				lvl.Range[0], lvl.Range[1], lvl.DeadEnds)
+		}
+		if lvl.LockedDoors < 0 {
+			return fmt.Errorf("levels %d-%d: locked_doors must not be negative, got %d",
+				lvl.Range[0], lvl.Range[1], lvl.LockedDoors)
+		}
+		if lvl.LockedDoors > 0 && len(cfg.Key.Name) == 0 {
+			return fmt.Errorf("levels %d-%d: locked doors need key names", lvl.Range[0], lvl.Range[1])
+		}
+		if lvl.SecretDoors < 0 || lvl.SecretCorridors < 0 {
+			return fmt.Errorf("levels %d-%d: secret_doors and secret_corridors must not be negative, got %d and %d",
+				lvl.Range[0], lvl.Range[1], lvl.SecretDoors, lvl.SecretCorridors)
+		}
+		if lvl.SearchChance < 0 || lvl.SearchChance > 100 {
+			return fmt.Errorf("levels %d-%d: search_chance must be in [0, 100], got %d",
+				lvl.Range[0], lvl.Range[1], lvl.SearchChance)
+		}
+		if lvl.SearchChance == 0 && lvl.SecretDoors+lvl.SecretCorridors > 0 {
+			return fmt.Errorf("levels %d-%d: secrets can't be found with zero search_chance", lvl.Range[0], lvl.Range[1])
+		}
+		if !validRange(lvl.Traps) {
+			return fmt.Errorf("levels %d-%d: invalid traps %v", lvl.Range[0], lvl.Range[1], lvl.Traps)
+		}
+		for name, weight := range lvl.TrapChances {
+			if _, err := dungeon.TrapTypeByName(name); err != nil {
+				return fmt.Errorf("levels %d-%d: trap_chances: %w, known trap types: %v",
+					lvl.Range[0], lvl.Range[1], err, dungeon.TrapTypeNames())
+			}
+			if weight < 0 {
+				return fmt.Errorf("levels %d-%d: trap_chances: weight of %s must not be negative, got %d",
+					lvl.Range[0], lvl.Range[1], name, weight)
+			}
+		}
+		for name, weight := range lvl.RoomWeights {
+			if _, err := dungeon.RoomTypeByName(name); err != nil {
+				return fmt.Errorf("levels %d-%d: room_weights: %w, known room types: %v",
+					lvl.Range[0], lvl.Range[1], err, dungeon.RoomTypeNames())
+			}
+			if weight < 0 {
+				return fmt.Errorf("levels %d-%d: room_weights: weight of %s must not be negative, got %d",
+					lvl.Range[0], lvl.Range[1], name, weight)
+			}
+		}
+	}
+	if cfg.CharacterStartParams.Speed < unit.MinSpeed {
+		return fmt.Errorf("character_start_params: speed must be at least %d, got %d",
+			unit.MinSpeed, cfg.CharacterStartParams.Speed)
+	}
+	for _, speed := range [][]int{cfg.Elixir.Haste, cfg.Elixir.Slow} {
+		if len(speed) != 2 || !validRange([2]int{speed[0], speed[1]}) {
+			return fmt.Errorf("elixir: haste and slow must be [min, max] ranges, got %v", speed)
+		}
+	}
+	if !validRange(cfg.Armor.Defense) || len(cfg.Armor.Name) == 0 {
+		return fmt.Errorf("armor: invalid defense %v or no names", cfg.Armor.Defense)
+	}
+	if !validRange(cfg.Trap.ArrowDamage) {
+		return fmt.Errorf("trap: invalid arrow_damage %v", cfg.Trap.ArrowDamage)
+	}
+	if !validRange(cfg.Vault.ItemsCount) {
+		return fmt.Errorf("vault: invalid items_count %v", cfg.Vault.ItemsCount)
+	}
+	if !validRange(cfg.Lair.EnemyCount) {
+		return fmt.Errorf("lair: invalid enemy_count %v", cfg.Lair.EnemyCount)
+	}
+	if cfg.Lair.TreasureMultiplier < 1 {
+		return fmt.Errorf("lair: treasure_multiplier must be at least 1, got %d", cfg.Lair.TreasureMultiplier)
+	}
+	if err := cfg.Endless.EnemyStats.validate(); err != nil {
+		return fmt.Errorf("endless: enemy_stats: %w", err)
+	}
+	if err := cfg.Endless.EnemyCount.validate(); err != nil {
+		return fmt.Errorf("endless: enemy_count: %w", err)
+	}
+	if err := cfg.Endless.Treasure.validate(); err != nil {
+		return fmt.Errorf("endless: treasure: %w", err)
+	}
+	if err := validateBossConfig(cfg); err != nil {
+		return fmt.Errorf("boss: %w", err)
+	}
+	if err := validateCombatConfig(cfg.Combat); err != nil {
+		return fmt.Errorf("combat: %w", err)
+	}
+	if cfg.Leveling.BaseXP < 1 {
+		return fmt.Errorf("leveling: base_xp must be at least 1, got %d", cfg.Leveling.BaseXP)
+	}
+	if cfg.Leveling.MaxHealth < 0 || cfg.Leveling.Strength < 0 || cfg.Leveling.Agility < 0 {
+		return fmt.Errorf("leveling: increments must not be negative, got %d, %d, %d",
+			cfg.Leveling.MaxHealth, cfg.Leveling.Strength, cfg.Leveling.Agility)
+	}
+	if err := validateEnemies(cfg); err != nil {
+		return fmt.Errorf("enemies: %w", err)
+	}
+	if cfg.Boss.XP < 0 {
+		return fmt.Errorf("boss: xp must not be negative, got %d", cfg.Boss.XP)
+	}
+	if len(cfg.Boss.Phases) > 0 && cfg.Boss.Speed < unit.MinSpeed {
+		return fmt.Errorf("boss: speed must be at least %d, got %d", unit.MinSpeed, cfg.Boss.Speed)
+	}
+	if err := validateLoot(cfg.Boss.Loot); err != nil {
+		return fmt.Errorf("boss: loot: %w", err)
+	}
+	for _, bonus := range [][]int{cfg.Shrine.MaxHealth, cfg.Shrine.Strength, cfg.Shrine.Agility} {
+		if len(bonus) != 2 || !validRange([2]int{bonus[0], bonus[1]}) {
+			return fmt.Errorf("shrine: bonuses must be [min, max] ranges, got %v", bonus)
+		}
+	}
+	return nil
+}
+
+// validateCampaignConfig checks the victory condition and that every level of the campaign
+// is covered by exactly one level range.
+func validateCampaignConfig(cfg *Config) error {
+	campaign := cfg.Campaign
+	if campaign.Levels < 1 {
+		return fmt.Errorf("levels must be at least 1, got %d", campaign.Levels)
+	}
+	victory, err := dungeon.VictoryByName(campaign.Victory)
+	if err != nil {
+		return fmt.Errorf("%w, known victory conditions: %v", err, dungeon.VictoryNames())
+	}
+	if victory == dungeon.VictoryKillBoss && len(cfg.Boss.Phases) == 0 {
+		return fmt.Errorf("victory %s needs the boss phases", campaign.Victory)
+	}
+	if victory == dungeon.VictoryRetrieveArtifact && campaign.Artifact == "" {
+		return fmt.Errorf("victory %s needs the artifact name", campaign.Victory)
+	}
+
+	covered := make(map[int]Level)
+	for _, lvl := range cfg.Levels {
+		if lvl.Range[0] < 1 || lvl.Range[0] > lvl.Range[1] {
+			return fmt.Errorf("invalid level range %v", lvl.Range)
+		}
+		for level := lvl.Range[0]; level <= lvl.Range[1]; level++ {
+			if other, ok := covered[level]; ok {
+				return fmt.Errorf("level %d is covered by both ranges %v and %v", level, other.Range, lvl.Range)
+			}
+			covered[level] = lvl
+		}
+	}
+	for level := 1; level <= campaign.Levels; level++ {
+		if _, ok := covered[level]; !ok {
+			return fmt.Errorf("level %d isn't covered by any level range", level)
+		}
+	}
+	return nil
+}
+
+// validateCombatConfig checks that the chances are probabilities with min_hit_chance <= base_hit_chance <= max_hit_chance,
+// the damage variance keeps the damage positive and a critical hit doesn't weaken the damage.
+func validateCombatConfig(c CombatConfig) error {
+	if c.MinHitChance < 0 || c.MinHitChance > c.BaseHitChance || c.BaseHitChance > c.MaxHitChance || c.MaxHitChance > 1 {
+		return fmt.Errorf("hit chances must hold 0 <= min_hit_chance <= base_hit_chance <= max_hit_chance <= 1, got %v, %v, %v",
+			c.MinHitChance, c.BaseHitChance, c.MaxHitChance)
+	}
+	if c.AgilityStep < 0 {
+		return fmt.Errorf("agility_step must not be negative, got %v", c.AgilityStep)
+	}
+	if c.DamageVariance < 0 || c.DamageVariance >= 1 {
+		return fmt.Errorf("damage_variance must be in [0, 1), got %v", c.DamageVariance)
+	}
+	if c.CritChance < 0 || c.CritChance > 1 {
+		return fmt.Errorf("crit_chance must be in [0, 1], got %v", c.CritChance)
+	}
+	if c.CritMultiplier < 1 {
+		return fmt.Errorf("crit_multiplier must be at least 1, got %v", c.CritMultiplier)
+	}
+	return nil
+}
+
+// validateBossConfig checks the boss segments and phases. The first phase must begin at 100 percent of health
+// and every next phase at a lower percent.
+func validateBossConfig(cfg *Config) error {
+	boss := cfg.Boss
+	if len(boss.Phases) == 0 {
+		return nil
+	}
+	if _, ok := cfg.EnemyHealth[boss.EnemyHealth]; !ok {
+		return fmt.Errorf("unknown enemy_health segment %q", boss.EnemyHealth)
+	}
+	if _, ok := cfg.EnemyAnimosity[boss.EnemyAnimosity]; !ok {
+		return fmt.Errorf("unknown enemy_animosity segment %q", boss.EnemyAnimosity)
+	}
+	if !validRange(boss.Treasure) {
+		return fmt.Errorf("invalid treasure %v", boss.Treasure)
+	}
+	for i, phase := range boss.Phases {
+		if i == 0 && phase.Health != 100 {
+			return fmt.Errorf("the first phase must begin at health 100, got %d", phase.Health)
+		}
+		if i > 0 && (phase.Health <= 0 || phase.Health >= boss.Phases[i-1].Health) {
+			return fmt.Errorf("phase %d: health must be in (0, %d), got %d", i+1, boss.Phases[i-1].Health, phase.Health)
+		}
+		if _, ok := cfg.EnemyStrength[phase.EnemyStrength]; !ok {
+			return fmt.Errorf("phase %d: unknown enemy_strength segment %q", i+1, phase.EnemyStrength)
+		}
+		if _, ok := cfg.EnemyAgility[phase.EnemyAgility]; !ok {
+			return fmt.Errorf("phase %d: unknown enemy_agility segment %q", i+1, phase.EnemyAgility)
+		}
+		if _, err := unit.BossBehaviourByName(phase.Behaviour); err != nil {
+			return fmt.Errorf("phase %d: %w, known behaviours: %v", i+1, err, unit.BossBehaviourNames())
+		}
+	}
+	return nil
+}
+
+// validRange checks that the [min, max] range isn't negative or reversed.
+func validRange(r [2]int) bool {
+	return r[0] >= 0 && r[0] <= r[1]
 }
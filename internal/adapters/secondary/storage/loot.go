@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+// ErrUnknownLoot is returned when there's no loot item with the requested name.
+var ErrUnknownLoot = errors.New("unknown loot item")
+
+// lootItems contains the constructors of the items enemies can drop by their config names.
+var lootItems = map[string]func(rng *common.RNG, cfg *Config) item.Item{
+	"food":   func(rng *common.RNG, cfg *Config) item.Item { return createFood(rng, cfg.Food) },
+	"elixir": func(rng *common.RNG, cfg *Config) item.Item { return createElixir(rng, cfg.Elixir) },
+	"scroll": func(rng *common.RNG, cfg *Config) item.Item { return createScroll(rng, cfg.Scroll) },
+	"weapon": func(rng *common.RNG, cfg *Config) item.Item { return createWeapon(rng, cfg.Weapon) },
+	"armor":  func(rng *common.RNG, cfg *Config) item.Item { return createArmor(rng, cfg.Armor) },
+}
+
+// lootItemNames returns sorted names of all loot items.
+func lootItemNames() []string {
+	names := make([]string, 0, len(lootItems))
+	for name := range lootItems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateLoot checks the item names and the drop chances of the loot table.
+func validateLoot(loot map[string]int) error {
+	for name, chance := range loot {
+		if _, ok := lootItems[name]; !ok {
+			return fmt.Errorf("%w: %q, known items: %v", ErrUnknownLoot, name, lootItemNames())
+		}
+		if chance < 0 || chance > 100 {
+			return fmt.Errorf("chance of %s must be in [0, 100], got %d", name, chance)
+		}
+	}
+	return nil
+}
+
+// rollLoot rolls every item of the loot table by its drop chance in percent and returns the dropped items.
+// The items are rolled in the order of their names, so a seed always gives the same loot.
+func rollLoot(rng *common.RNG, cfg *Config, loot map[string]int) []item.Item {
+	names := make([]string, 0, len(loot))
+	for name := range loot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var items []item.Item
+	for _, name := range names {
+		if rng.Intn(100) < loot[name] {
+			items = append(items, lootItems[name](rng, cfg))
+		}
+	}
+	return items
+}
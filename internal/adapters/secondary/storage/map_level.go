@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// loadLevelMaps reads and parses the ASCII maps of the levels. Map paths are relative to the config directory.
+func loadLevelMaps(cfg *Config, dir string) error {
+	for i := range cfg.Levels {
+		lvl := &cfg.Levels[i]
+		if lvl.Map == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, lvl.Map))
+		if err != nil {
+			return fmt.Errorf("levels %d-%d: %w", lvl.Range[0], lvl.Range[1], err)
+		}
+		layout, err := dungeon.ParseASCIIMap(string(data))
+		if err != nil {
+			return fmt.Errorf("levels %d-%d: map %s: %w", lvl.Range[0], lvl.Range[1], lvl.Map, err)
+		}
+		for _, enemy := range layout.Enemies {
+			if _, ok := cfg.enemyByGlyph(enemy.Symbol); !ok {
+				return fmt.Errorf("levels %d-%d: map %s: no config of enemy %q", lvl.Range[0], lvl.Range[1], lvl.Map, enemy.Symbol)
+			}
+		}
+		lvl.layout = layout
+	}
+	return nil
+}
+
+// generateMapLevel creates the level from its hand-authored map.
+// Items and enemies are created from the config by their map letters, the level has no random extras:
+// no special rooms, locked doors, secrets or traps. The stairs up are put next to the start position,
+// the artifact of the campaign next to the exit, each on a tile holding nothing else.
+// The boss of the last level guards the exit, which is sealed until the boss is defeated.
+func generateMapLevel(level int, cfg *Config, lvlCfg Level, player *unit.Character, rng *common.RNG) dungeon.Dungeon {
+	d := lvlCfg.layout.NewLayout()
+	d.RNG = rng
+	d.LevelNumber = level
+	d.SearchChance = lvlCfg.SearchChance
+
+	player.SetCoords(lvlCfg.layout.Start)
+	d.Player = player
+	for i := range d.Rooms {
+		if d.Rooms[i].Type == dungeon.RoomStart {
+			d.Rooms[i].Visible = dungeon.FogClean
+		}
+	}
+
+	for _, marker := range lvlCfg.layout.Items {
+		var it item.Item
+		switch marker.Symbol {
+		case 'f':
+			it = createFood(rng, cfg.Food)
+		case 'e':
+			it = createElixir(rng, cfg.Elixir)
+		case 's':
+			it = createScroll(rng, cfg.Scroll)
+		case 'w':
+			it = createWeapon(rng, cfg.Weapon)
+		case 'a':
+			it = createArmor(rng, cfg.Armor)
+		}
+		it.SetCoords(marker.Coords)
+		d.Items = append(d.Items, it)
+	}
+	for _, marker := range lvlCfg.layout.Enemies {
+		name, _ := cfg.enemyByGlyph(marker.Symbol)
+		enemy := createEnemy(rng, cfg, name, lvlCfg.Treasure)
+		enemy.SetCoords(marker.Coords)
+		d.Enemies = append(d.Enemies, enemy)
+	}
+	if level > 1 {
+		stairs := freeTileNear(&d, lvlCfg.layout.Start)
+		d.StairsUp = &stairs
+	}
+	if cfg.IsLastLevel(level) && cfg.Victory() == dungeon.VictoryRetrieveArtifact {
+		artifact := freeTileNear(&d, d.Exit)
+		d.Artifact = &artifact
+	}
+	if cfg.IsLastLevel(level) && len(cfg.Boss.Phases) > 0 {
+		boss := createBoss(rng, cfg)
+		boss.SetCoords(freeTileNear(&d, d.Exit))
+		d.Enemies = append(d.Enemies, boss)
+		d.ExitSealed = true
+	}
+	return d
+}
+
+// freeTileNear returns the free floor tile nearest to c on the map level.
+// Parsed maps leave enough free tiles for the stairs, the artifact and the boss, so running out of them is a bug.
+func freeTileNear(d *dungeon.Dungeon, c common.Coords) common.Coords {
+	tile, ok := d.FreeTileNear(c)
+	if !ok {
+		panic(fmt.Sprintf("no free floor tile near (%d, %d) on the map level", c.X, c.Y))
+	}
+	return tile
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+const (
+	// CurrentSaveVersion is the version of the save format written by SaveGameState.
+	// It's raised only when the format changes in a way which needs a migration of older saves.
+	CurrentSaveVersion = 2
+
+	// MinSaveVersion is the version of the saves written before the format was versioned,
+	// they have no version field. Every save from this version on can be migrated.
+	MinSaveVersion = 1
+)
+
+// ErrSaveTooNew is returned when the save was written by a newer version of the game.
+var ErrSaveTooNew = errors.New("save format is too new")
+
+// SaveVersionError describes a save which can't be loaded because it was written by a newer version of the game.
+// It wraps ErrSaveTooNew, so it can be checked with errors.Is.
+type SaveVersionError struct {
+	Version int // Version found in the save file
+}
+
+// Error implements the error interface.
+func (e *SaveVersionError) Error() string {
+	return fmt.Sprintf("%v: version %d, supported versions %d..%d",
+		ErrSaveTooNew, e.Version, MinSaveVersion, CurrentSaveVersion)
+}
+
+// Unwrap returns ErrSaveTooNew.
+func (e *SaveVersionError) Unwrap() error {
+	return ErrSaveTooNew
+}
+
+// saveMigration upgrades a raw save document by exactly one version.
+// The document is the result of unmarshaling the save file into a generic map,
+// the config is the one of the game, which the data missing in older saves is taken from.
+type saveMigration func(doc map[string]any, cfg *Config) error
+
+// migrationConfig loads the config for the migrations. It's only loaded if the save needs migrating.
+var migrationConfig = func() (*Config, error) {
+	return LoadDungeonConfig(DungeonConfigFile)
+}
+
+// saveMigrations maps a save version to the migration which upgrades it to the next version.
+// Every version from MinSaveVersion to CurrentSaveVersion-1 must have a migration.
+var saveMigrations = map[int]saveMigration{
+	1: migrateV1ToV2,
+}
+
+// decodeSave upgrades the raw save file data step by step to CurrentSaveVersion
+// and decodes it into DungeonData.
+// Returns *SaveVersionError if the save was written by a newer version of the game.
+func decodeSave(data []byte) (*DungeonData, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	version, err := saveVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version < MinSaveVersion {
+		return nil, fmt.Errorf("invalid save version %d", version)
+	}
+	if version > CurrentSaveVersion {
+		return nil, &SaveVersionError{Version: version}
+	}
+
+	var cfg *Config
+	if version < CurrentSaveVersion {
+		if cfg, err = migrationConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load config for migration: %w", err)
+		}
+	}
+	for v := version; v < CurrentSaveVersion; v++ {
+		migrate, ok := saveMigrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from save version %d", v)
+		}
+		if err := migrate(doc, cfg); err != nil {
+			return nil, fmt.Errorf("migration from save version %d: %w", v, err)
+		}
+		doc["version"] = v + 1
+	}
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var dungeonData DungeonData
+	if err := json.Unmarshal(upgraded, &dungeonData); err != nil {
+		return nil, err
+	}
+	return &dungeonData, nil
+}
+
+// saveVersion returns the version of the raw save document, 1 if the field is missing.
+func saveVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return MinSaveVersion, nil
+	}
+	num, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid save version %v", raw)
+	}
+	version, err := num.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("invalid save version %v", raw)
+	}
+	return int(version), nil
+}
+
+// object returns the nested JSON object stored under the key, or an empty one.
+func object(doc map[string]any, key string) map[string]any {
+	if obj, ok := doc[key].(map[string]any); ok {
+		return obj
+	}
+	return map[string]any{}
+}
+
+// migrateV1ToV2 upgrades the saves written before the format was versioned: the game had one save file,
+// no seed and the enemy types built into the game, which are replaced with the enemy kinds of the config. The data which appeared later and isn't set here,
+// like locks, secrets, traps, the boss, visited levels, statuses, armor and loot, is missing from such saves
+// and is read as empty.
+func migrateV1ToV2(doc map[string]any, cfg *Config) error {
+	addSlotMeta(doc)
+	doc["seed"] = 0
+	doc["draws"] = 0
+	object(doc, "meta")["seed"] = 0
+	// there were no secrets to find
+	doc["search_chance"] = 0
+	character := object(doc, "character")
+	character["level"] = 1
+	character["xp"] = 0
+	// the player gets the start speed and is ready to act
+	base := object(character, "base_data")
+	base["speed"] = cfg.CharacterStartParams.Speed
+	base["energy"] = unit.ActionEnergy
+	character["base_data"] = base
+	doc["character"] = character
+	return addEnemyKinds(doc, cfg)
+}
+
+// addSlotMeta adds the save slot metadata. The slot name is taken from the file name when the slots are listed.
+func addSlotMeta(doc map[string]any) {
+	character := object(doc, "character")
+	meta := map[string]any{
+		"level":      doc["level"],
+		"treasure":   object(character, "stats")["treasures"],
+		"health":     object(character, "base_data")["health"],
+		"max_health": character["max_health"],
+	}
+	doc["meta"] = meta
+}
+
+// legacyEnemyTypes contains the config names of the enemy kinds by the enemy types of older saves.
+var legacyEnemyTypes = map[int]string{
+	0: "zombie",
+	1: "vampire",
+	2: "ghost",
+	3: "ogr",
+	4: "snake_wizard",
+}
+
+// addEnemyKinds replaces the enemy types with the enemy kinds of the config.
+// Every enemy gets the speed of its kind like a new one and starts without energy.
+func addEnemyKinds(doc map[string]any, cfg *Config) error {
+	for _, level := range saveLevels(doc) {
+		enemies, _ := level["enemies"].([]any)
+		for _, raw := range enemies {
+			enemy, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			enemyType, err := strconv.Atoi(fmt.Sprint(enemy["enemy_type"]))
+			if err != nil {
+				return fmt.Errorf("invalid enemy type %v", enemy["enemy_type"])
+			}
+			name, ok := legacyEnemyTypes[enemyType]
+			if !ok {
+				return fmt.Errorf("unknown enemy type %d", enemyType)
+			}
+			enemyCfg, ok := cfg.Enemies[name]
+			if !ok {
+				return fmt.Errorf("enemy type %d: no config of enemy %q", enemyType, name)
+			}
+			kind, err := enemyKind(name, enemyCfg)
+			if err != nil {
+				return fmt.Errorf("enemy %s: %w", name, err)
+			}
+			enemy["kind"] = EnemyKindToDTO(kind)
+			delete(enemy, "enemy_type")
+			enemyBase := object(enemy, "base_data")
+			enemyBase["speed"] = enemyCfg.Speed
+			enemyBase["energy"] = 0
+			enemy["base_data"] = enemyBase
+		}
+	}
+	return nil
+}
+
+// saveLevels returns the raw documents of the current level and the visited levels of the save.
+func saveLevels(doc map[string]any) []map[string]any {
+	levels := []map[string]any{doc}
+	if floors, ok := doc["floors"].([]any); ok {
+		for _, floor := range floors {
+			if level, ok := floor.(map[string]any); ok {
+				levels = append(levels, level)
+			}
+		}
+	}
+	return levels
+}
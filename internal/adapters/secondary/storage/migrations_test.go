@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// testConfigFile is the game config seen from the package directory, where the tests run
+const testConfigFile = "../../../../configs/dungeon_config.yaml"
+
+// loadTestConfig loads the game config and makes the migrations use it.
+func loadTestConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := LoadDungeonConfig(testConfigFile)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	prev := migrationConfig
+	migrationConfig = func() (*Config, error) { return cfg, nil }
+	t.Cleanup(func() { migrationConfig = prev })
+	return cfg
+}
+
+// generateTestSave generates a level of a fresh game and converts it to the save data.
+func generateTestSave(t *testing.T, cfg *Config, level int, seed int64) DungeonData {
+	t.Helper()
+	d := GenerateDungeonFromConfig(level, cfg, nil, common.NewRNG(seed))
+	return DungeonToDTO(d)
+}
+
+func TestDecodeSaveMigratesUnversionedSave(t *testing.T) {
+	cfg := loadTestConfig(t)
+	raw, err := os.ReadFile("testdata/save_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := decodeSave(raw)
+	if err != nil {
+		t.Fatalf("decodeSave: %v", err)
+	}
+	if data.Version != CurrentSaveVersion {
+		t.Errorf("version = %d, want %d", data.Version, CurrentSaveVersion)
+	}
+	if data.LevelNumber != 3 {
+		t.Errorf("level = %d, want 3", data.LevelNumber)
+	}
+	if data.Meta.Level != 3 {
+		t.Errorf("meta level = %d, want 3", data.Meta.Level)
+	}
+	if data.Player.Level != 1 || data.Player.XP != 0 {
+		t.Errorf("character level %d with %d xp, want level 1 with 0 xp", data.Player.Level, data.Player.XP)
+	}
+	if data.Player.Unit.Speed != cfg.CharacterStartParams.Speed || data.Player.Unit.Energy != unit.ActionEnergy {
+		t.Errorf("player speed %d, energy %d, want %d, %d",
+			data.Player.Unit.Speed, data.Player.Unit.Energy, cfg.CharacterStartParams.Speed, unit.ActionEnergy)
+	}
+
+	if len(data.Enemies) == 0 {
+		t.Fatal("no enemies after migration")
+	}
+	kinds := make(map[string]bool)
+	for i, enemy := range data.Enemies {
+		kinds[enemy.Kind.Name] = true
+		enemyCfg, ok := cfg.Enemies[enemy.Kind.Name]
+		if !ok {
+			t.Errorf("enemy %d: kind %q isn't in the config", i, enemy.Kind.Name)
+			continue
+		}
+		if enemy.Kind.Title != enemyCfg.Title || enemy.Kind.Glyph != enemyCfg.Glyph {
+			t.Errorf("enemy %d: kind %q is %q %q, want %q %q from the config",
+				i, enemy.Kind.Name, enemy.Kind.Title, enemy.Kind.Glyph, enemyCfg.Title, enemyCfg.Glyph)
+		}
+		if enemy.Unit.Speed != enemyCfg.Speed || enemy.Unit.Energy != 0 {
+			t.Errorf("enemy %d (%s): speed %d, energy %d, want %d, 0",
+				i, enemy.Kind.Name, enemy.Unit.Speed, enemy.Unit.Energy, enemyCfg.Speed)
+		}
+	}
+	if !kinds["zombie"] || !kinds["ghost"] || len(kinds) != 2 {
+		t.Errorf("enemy kinds %v, want the zombies and ghosts of the save", kinds)
+	}
+}
+
+func TestDecodeSaveRejectsNewerVersion(t *testing.T) {
+	_, err := decodeSave([]byte(`{"version": 3}`))
+	var versionErr *SaveVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("decodeSave error = %v, want *SaveVersionError", err)
+	}
+	if versionErr.Version != 3 {
+		t.Errorf("version = %d, want 3", versionErr.Version)
+	}
+	if !errors.Is(err, ErrSaveTooNew) {
+		t.Errorf("error %v doesn't wrap ErrSaveTooNew", err)
+	}
+}
+
+func TestDecodeSaveRejectsInvalidVersion(t *testing.T) {
+	for _, raw := range []string{`{"version": 0}`, `{"version": "2"}`, `{"version": 1.5}`, `not json`} {
+		if _, err := decodeSave([]byte(raw)); err == nil {
+			t.Errorf("decodeSave(%s) succeeded", raw)
+		}
+	}
+}
+
+func TestDecodeSaveRoundTrip(t *testing.T) {
+	cfg := loadTestConfig(t)
+	// a current save never needs the config
+	migrationConfig = func() (*Config, error) { return nil, errors.New("config loaded for a current save") }
+
+	for _, level := range []int{1, 5, cfg.LastLevel()} {
+		data := generateTestSave(t, cfg, level, 42)
+		data.Version = CurrentSaveVersion
+		want, err := json.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := decodeSave(want)
+		if err != nil {
+			t.Fatalf("level %d: decodeSave: %v", level, err)
+		}
+		got, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("level %d: save changed after a round trip\ngot:  %s\nwant: %s", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ReplaysDir defines the directory where replays of the runs are stored
+	ReplaysDir = "internal/adapters/secondary/storage/replays"
+
+	// MaxReplays specifies how many replay files are kept, the oldest ones are deleted
+	MaxReplays = 20
+
+	// ReplayVersion is the version of the replay format
+	ReplayVersion = 1
+)
+
+// Replay action kinds.
+const (
+	ReplayMove   = "move"   // Player's step or attack in a direction
+	ReplaySelect = "select" // Selection of an inventory item
+	ReplaySearch = "search" // Search for secrets around the player
+)
+
+// ErrReplayVersion is returned when the replay file has unsupported format version.
+var ErrReplayVersion = errors.New("unsupported replay version")
+
+// ReplayActionData is one player action sent to the game use cases.
+type ReplayActionData struct {
+	Kind      string `json:"kind"`           // ReplayMove, ReplaySelect or ReplaySearch
+	Direction int    `json:"dir,omitempty"`  // Direction of the move
+	ItemType  int    `json:"item,omitempty"` // Type of the selected inventory item
+	Index     int    `json:"num,omitempty"`  // Index of the selected inventory item
+}
+
+// ReplayData contains everything needed to play a run again: the seed of a new game
+// or the saved state the run was loaded from, and the sequence of player actions.
+type ReplayData struct {
+	Version   int                `json:"version"`           // Replay format version
+	Seed      int64              `json:"seed"`              // Seed of the new game
+	Endless   bool               `json:"endless,omitempty"` // The new game is in the endless mode
+	Start     *DungeonData       `json:"start,omitempty"`   // Loaded game state, nil for a new game
+	StartedAt time.Time          `json:"started_at"`        // Start time of the run
+	Actions   []ReplayActionData `json:"actions"`           // Player actions in order
+}
+
+// ReplayPath returns the file path of a replay started at the given time with the given seed.
+func ReplayPath(startedAt time.Time, seed int64) string {
+	return filepath.Join(ReplaysDir, fmt.Sprintf("%s_%d.json", startedAt.Format("20060102-150405"), seed))
+}
+
+// SaveReplay atomically writes the replay to the file and deletes the oldest replays above MaxReplays.
+func SaveReplay(path string, data ReplayData) error {
+	data.Version = ReplayVersion
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, dataBytes, 0o644); err != nil {
+		return err
+	}
+	return removeOldReplays()
+}
+
+// LoadReplay reads the replay from the file.
+// The starting game state, if any, is migrated like a usual save.
+func LoadReplay(path string) (*ReplayData, error) {
+	dataBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		ReplayData
+		Start json.RawMessage `json:"start,omitempty"`
+	}
+	if err := json.Unmarshal(dataBytes, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Version != ReplayVersion {
+		return nil, fmt.Errorf("%w: %d", ErrReplayVersion, raw.Version)
+	}
+	replay := raw.ReplayData
+	replay.Start = nil
+	if len(raw.Start) > 0 && string(raw.Start) != "null" {
+		start, err := decodeSave(raw.Start)
+		if err != nil {
+			return nil, err
+		}
+		replay.Start = start
+	}
+	return &replay, nil
+}
+
+// LatestReplay returns the path of the most recent replay file.
+func LatestReplay() (string, error) {
+	files, err := replayFiles()
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", os.ErrNotExist
+	}
+	return files[len(files)-1], nil
+}
+
+// replayFiles returns paths of all replay files, oldest first.
+// The file names start with the start time, so the name order is the time order.
+func replayFiles() ([]string, error) {
+	entries, err := os.ReadDir(ReplaysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		files = append(files, filepath.Join(ReplaysDir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// removeOldReplays deletes the oldest replay files to keep at most MaxReplays.
+func removeOldReplays() error {
+	files, err := replayFiles()
+	if err != nil {
+		return err
+	}
+	for len(files) > MaxReplays {
+		if err := os.Remove(files[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
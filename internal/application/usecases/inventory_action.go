@@ -14,13 +14,16 @@ type InventoryActionUseCase struct {
 	dungeon          *dungeon.Dungeon
 	view             render.View
 	selectedItemType item.Type
+	replay           *ReplayRecorder
 }
 
 // NewInventoryAction creates new inventory use case instance.
-func NewInventoryAction(dung *dungeon.Dungeon, view *render.View) *InventoryActionUseCase {
+// Item selections are recorded by the replay recorder of the run.
+func NewInventoryAction(dung *dungeon.Dungeon, view *render.View, replay *ReplayRecorder) *InventoryActionUseCase {
 	return &InventoryActionUseCase{
 		dungeon: dung,
 		view:    *view,
+		replay:  replay,
 	}
 }
 
@@ -41,6 +44,9 @@ func (uc *InventoryActionUseCase) Execute(itemType item.Type) {
 	case item.WeaponType:
 		uc.view.RenderWeapon(player)
 		uc.selectedItemType = item.WeaponType
+	case item.ArmorType:
+		uc.view.RenderArmor(player)
+		uc.selectedItemType = item.ArmorType
 	case item.ElixirType:
 		uc.view.RenderElixirs(player.Inventory.Elixirs)
 		uc.selectedItemType = item.ElixirType
@@ -62,11 +68,12 @@ func (uc *InventoryActionUseCase) Execute(itemType item.Type) {
 }
 
 // Select chooses and uses inventory item by index.
-// Handles weapon equipping, consumable usage, and updates player stats.
+// Handles weapon and armor equipping, consumable usage, and updates player stats.
 func (uc *InventoryActionUseCase) Select(num int) {
 	if uc.selectedItemType == item.EmptyType {
 		return
 	}
+	uc.replay.RecordSelect(uc.selectedItemType, num)
 	player, _ := uc.dungeon.Player.(*unit.Character)
 
 	switch uc.selectedItemType {
@@ -89,6 +96,23 @@ func (uc *InventoryActionUseCase) Select(num int) {
 				player.ChooseWeapon(&newWeapon)
 			}
 		}
+	case item.ArmorType:
+		if len(player.Inventory.Armors) >= num {
+			if num == 0 {
+				if player.TakeOffArmor() {
+					uc.dungeon.ReplaceEventData("You put the armor in the backpack")
+				}
+			} else {
+				armor := &player.Inventory.Armors[num-1]
+				if player.CurrentArmor != nil && *player.CurrentArmor == *armor {
+					return
+				}
+				newArmor := *armor
+				uc.dungeon.ReplaceEventData(fmt.Sprintf("You put on the %s", armor.Name))
+				player.DropArmor(uc.dungeon)
+				player.ChooseArmor(&newArmor)
+			}
+		}
 	case item.ElixirType:
 		if len(player.Inventory.Elixirs) > num {
 			elixir := &player.Inventory.Elixirs[num]
@@ -118,3 +142,11 @@ func (uc *InventoryActionUseCase) Select(num int) {
 	}
 	uc.view.RenderStatistic(*player)
 }
+
+// SelectItem chooses and uses inventory item of the specified type by index without showing the inventory.
+// It's used to play the recorded selections back.
+func (uc *InventoryActionUseCase) SelectItem(itemType item.Type, num int) {
+	uc.selectedItemType = itemType
+	uc.Select(num)
+	uc.selectedItemType = item.EmptyType
+}
@@ -25,37 +25,73 @@ const (
 	Win
 	// NextLevel - Player step on Exit tile
 	NextLevel
+	// PreviousLevel - Player step on the stairs up
+	PreviousLevel
 )
 
 // PlayerActionUseCase encapsulates the logic for processing player input and managing dungeon state.
 type PlayerActionUseCase struct {
 	character *unit.Character
 	dungeon   dungeon.Dungeon
+	floors    dungeon.Floors // visited levels of the run the player has left
 	view      render.View
 	cfg       *storage.Config
 	cancel    context.CancelFunc
+	slot      string // save slot of the current run
+	seed      int64  // user-supplied seed for new games, 0 means a new random seed for every game
+	replay    *ReplayRecorder
+	playback  bool // a replay is played, so nothing is saved
 }
 
 // NewPlayerActionUseCase creates a new instance of PlayerActionUseCase with provided dependencies.
-func NewPlayerActionUseCase(character *unit.Character, dung dungeon.Dungeon, view *render.View, cfg *storage.Config, cancel context.CancelFunc) *PlayerActionUseCase {
+// New games are seeded with seed, or with a random seed if it's 0.
+func NewPlayerActionUseCase(character *unit.Character, dung dungeon.Dungeon, view *render.View, cfg *storage.Config, cancel context.CancelFunc, seed int64) *PlayerActionUseCase {
 	return &PlayerActionUseCase{
 		character: character,
 		dungeon:   dung,
+		floors:    dungeon.Floors{},
 		view:      *view,
 		cfg:       cfg,
 		cancel:    cancel,
+		seed:      seed,
+		replay:    &ReplayRecorder{},
 	}
 }
 
+// Replay returns the recorder of the current run's replay.
+func (uc *PlayerActionUseCase) Replay() *ReplayRecorder {
+	return uc.replay
+}
+
 // GetDungeon returns a pointer to the current dungeon instance.
 func (uc *PlayerActionUseCase) GetDungeon() *dungeon.Dungeon {
 	return &uc.dungeon
 }
 
 // Execute processes the player's directional input, updates dungeon state, handles rendering,
-// checks for death or level completion, and progresses to the next level if needed.
+// checks for death, the campaign victory or level completion, and progresses to the next level if needed.
 func (uc *PlayerActionUseCase) Execute(direction unit.Direction) ActionResult {
-	logic.HandleAction(direction, &uc.dungeon)
+	uc.replay.RecordMove(direction)
+	logic.HandleAction(direction, &uc.dungeon, uc.rules())
+	return uc.finishTurn()
+}
+
+// Search spends the player's turn on searching for secret doors and corridors around.
+// The rest of the turn is processed like after a move.
+func (uc *PlayerActionUseCase) Search() ActionResult {
+	uc.replay.RecordSearch()
+	logic.HandleSearch(&uc.dungeon, uc.rules())
+	return uc.finishTurn()
+}
+
+// rules returns the configured rules of the turn.
+func (uc *PlayerActionUseCase) rules() logic.Rules {
+	return logic.Rules{Combat: uc.cfg.CombatFormula(), Leveling: uc.cfg.LevelingRules()}
+}
+
+// finishTurn updates dungeon state after the player's action, handles rendering,
+// checks for death, the campaign victory or level completion, and progresses to the next level if needed.
+func (uc *PlayerActionUseCase) finishTurn() ActionResult {
 	uc.dungeon.Update()
 	if uc.character.IsDead() {
 		uc.view.RenderCharacterDeathWindow()
@@ -64,26 +100,74 @@ func (uc *PlayerActionUseCase) Execute(direction unit.Direction) ActionResult {
 		return GameOver
 	}
 
-	player, _ := uc.dungeon.Player.(*unit.Character)
-
-	if uc.dungeon.IsExit() {
+	if uc.dungeon.TakeArtifact() {
+		uc.dungeon.AddEventData("You have found the " + uc.cfg.Campaign.Artifact + "! Carry it out through the exit.")
+	}
+	last := uc.cfg.IsLastLevel(uc.dungeon.LevelNumber)
+	if last && uc.dungeon.Won(uc.cfg.Victory()) {
+		uc.view.RenderCharacterWinWindow()
+		uc.SaveStats()
+		return Win
+	}
+	if last && uc.dungeon.IsExit() && uc.cfg.Victory() == dungeon.VictoryRetrieveArtifact {
+		uc.dungeon.AddEventData("You can't leave without the " + uc.cfg.Campaign.Artifact + "!")
+	}
 
-		if uc.dungeon.LevelNumber == 21 {
-			uc.view.RenderCharacterWinWindow()
-			uc.SaveStats()
-			return Win
+	// a trapdoor drops the player to the next level the same way as the exit;
+	// there are no trapdoors on the last level
+	if !last && (uc.dungeon.IsExit() || uc.dungeon.OnTrapdoor()) {
+		fell := !uc.dungeon.IsExit()
+		uc.changeLevel(uc.dungeon.LevelNumber + 1)
+		if fell {
+			uc.dungeon.AddEventData("You fell through a trapdoor!")
 		}
-		uc.dungeon = storage.GenerateDungeonFromConfig(uc.dungeon.LevelNumber+1, uc.cfg, player)
 		uc.view.GameWindow.Erase()
 		if err := uc.SaveGame(); err != nil {
 			panic(fmt.Sprintf("save failed: %v", err))
 		}
 		return NextLevel
 	}
+	if uc.dungeon.OnStairsUp() {
+		uc.changeLevel(uc.dungeon.LevelNumber - 1)
+		uc.view.GameWindow.Erase()
+		if err := uc.SaveGame(); err != nil {
+			panic(fmt.Sprintf("save failed: %v", err))
+		}
+		return PreviousLevel
+	}
 	uc.view.Render(uc.dungeon)
 	return ContinueGame
 }
 
+// changeLevel leaves the current level for the given one.
+// The current level is kept in the visited floors together with its keys carried by the player,
+// keys fit the doors of their level only. A visited level is restored exactly as it was left,
+// the player arrives next to the stairs leading back; a level visited for the first time is generated.
+func (uc *PlayerActionUseCase) changeLevel(level int) {
+	up := level < uc.dungeon.LevelNumber
+	left := uc.dungeon
+	left.HeldKeys = uc.character.Inventory.Keys
+	uc.character.Inventory.Keys = nil
+	uc.floors.Store(left)
+
+	next, ok := uc.floors.Take(level)
+	if !ok {
+		uc.dungeon = storage.GenerateDungeonFromConfig(level, uc.cfg, uc.character, left.RNG)
+		return
+	}
+	next.RNG = left.RNG
+	next.Player = uc.character
+	uc.character.Inventory.Keys = next.HeldKeys
+	next.HeldKeys = nil
+	arrival := next.Entrance()
+	if up {
+		arrival = next.Exit
+	}
+	uc.character.SetCoords(next.ArrivalPoint(arrival))
+	next.Update()
+	uc.dungeon = next
+}
+
 // RenderInitial forces an immediate render of the game state
 func (uc *PlayerActionUseCase) RenderInitial() {
 	uc.view.Render(uc.dungeon)
@@ -94,11 +178,16 @@ func (uc *PlayerActionUseCase) RenderMainMenu() {
 	uc.view.RenderMainWindow()
 }
 
-// SaveGame save current game to JSON file
+// SaveGame save current game to JSON file of the current save slot and the replay of the run.
+// Does nothing while a replay is played.
 func (uc *PlayerActionUseCase) SaveGame() error {
+	if uc.playback {
+		return nil
+	}
 	stor := storage.NewJSONDungeonStorage()
 	storDTO := storage.DungeonToDTO(uc.dungeon)
-	err := stor.SaveGameState(storDTO)
+	storDTO.Floors = storage.FloorsToDTO(uc.floors)
+	err := stor.SaveGameState(uc.slot, storDTO)
 	if err != nil {
 		return fmt.Errorf("save error: %w", err)
 	}
@@ -109,15 +198,22 @@ func (uc *PlayerActionUseCase) SaveGame() error {
 			return fmt.Errorf("save error: %w", err)
 		}
 	}
+	if err := uc.replay.Save(); err != nil {
+		return err
+	}
 	uc.view.RenderInfo([]string{"Game saved successfully!"})
 	return nil
 }
 
-// SaveStats - push player's stats to leaderboard
+// SaveStats - push player's stats to leaderboard and save the replay of the finished run.
+// Does nothing while a replay is played.
 func (uc *PlayerActionUseCase) SaveStats() error {
+	if uc.playback {
+		return nil
+	}
 	stor := storage.NewJSONDungeonStorage()
 	player, ok := uc.dungeon.Player.(*unit.Character)
-	if err := stor.DeleteSave(); err != nil {
+	if err := stor.DeleteSave(uc.slot); err != nil {
 		return fmt.Errorf("save error: %w", err)
 	}
 	if ok {
@@ -126,58 +222,145 @@ func (uc *PlayerActionUseCase) SaveStats() error {
 			return fmt.Errorf("save error: %w", err)
 		}
 	}
+	if err := uc.replay.Save(); err != nil {
+		return err
+	}
 	uc.view.RenderInfo([]string{"Stats saved successfully!"})
 	return nil
 }
 
-// LoadGame load last game from JSON
-func (uc *PlayerActionUseCase) LoadGame() (*dungeon.Dungeon, error) {
+// LoadGame load game from JSON file of the specified save slot and starts recording its replay.
+// The leaderboard record of the loaded run is removed only when the game is loaded successfully
+func (uc *PlayerActionUseCase) LoadGame(slot string) (*dungeon.Dungeon, error) {
 	stor := storage.NewJSONDungeonStorage()
-	loadedDTO, err := stor.LoadGameState()
+	loadedDTO, err := stor.LoadGameState(slot)
 	if err != nil {
 		return nil, fmt.Errorf("load error: %w", err)
 	}
-	loadedDungeon := storage.DTOToDungeon(*loadedDTO)
+	if err := uc.setLoadedGame(*loadedDTO); err != nil {
+		return nil, err
+	}
+	// the run continues, so its record saved with the last level leaves the leaderboard
+	storage.RemoveLastRecord(loadedDTO.Player.Stats.Endless)
+	uc.slot = slot
+	uc.playback = false
+	uc.replay.Start(loadedDTO.Seed, loadedDTO.Player.Stats.Endless, loadedDTO)
+	return &uc.dungeon, nil
+}
+
+// setLoadedGame makes the saved game state current.
+func (uc *PlayerActionUseCase) setLoadedGame(loadedDTO storage.DungeonData) error {
+	loadedDungeon := storage.DTOToDungeon(loadedDTO)
 	player, ok := loadedDungeon.Player.(*unit.Character)
 	if !ok {
-		return nil, fmt.Errorf("invalid player type in saved game")
+		return fmt.Errorf("invalid player type in saved game")
 	}
-	cfg, err := storage.LoadDungeonConfig("configs/dungeon_config.yaml")
+	cfg, err := storage.LoadDungeonConfig(storage.DungeonConfigFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 	uc.dungeon = loadedDungeon
+	uc.floors = storage.DTOToFloors(loadedDTO.Floors)
 	uc.character = player
 	uc.cfg = cfg
+	uc.cfg.EndlessMode = player.Stats.Endless
 	if uc.character.CurrentWeapon != nil {
 		uc.character.Strength -= uc.character.CurrentWeapon.Strength
 		uc.character.CurrentWeapon = nil
 	}
-	return &loadedDungeon, nil
+	return nil
+}
+
+// StartPlayback restores the starting state of the recorded run: the loaded game or a new game with the recorded seed.
+// Nothing is saved or recorded until a new game is started or loaded.
+func (uc *PlayerActionUseCase) StartPlayback(replay *storage.ReplayData) error {
+	if replay.Start != nil {
+		if err := uc.setLoadedGame(*replay.Start); err != nil {
+			return err
+		}
+	} else {
+		cfg, err := storage.LoadDungeonConfig(storage.DungeonConfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		cfg.EndlessMode = replay.Endless
+		uc.dungeon = storage.GenerateDungeonFromConfig(1, cfg, nil, common.NewRNG(replay.Seed))
+		uc.dungeon.AddEventData(fmt.Sprintf("Replay of the game with seed %d", replay.Seed))
+		uc.floors = dungeon.Floors{}
+		uc.character, _ = uc.dungeon.Player.(*unit.Character)
+		uc.cfg = cfg
+	}
+	uc.playback = true
+	uc.replay.Stop()
+	uc.view.GameWindow.Clear()
+	return nil
 }
 
-// NewGame create new game
-func (uc *PlayerActionUseCase) NewGame() {
-	cfgGame, err := storage.LoadDungeonConfig("configs/dungeon_config.yaml")
+// FreeSlot returns the save slot for a new game, so the other runs stay untouched.
+// Returns storage.ErrNoFreeSlot if all slots are taken and the player has to choose one to overwrite.
+func (uc *PlayerActionUseCase) FreeSlot() (string, error) {
+	return storage.NewJSONDungeonStorage().FreeSlot()
+}
+
+// NewGame create new game in the given save slot, the previous run saved there is deleted with its backups.
+// The game is seeded with the user-supplied seed or a new random one; the seed is shown in the info window.
+// An endless game has no last level and goes deeper until the player dies.
+// Returns an error if the slot can't be cleared.
+func (uc *PlayerActionUseCase) NewGame(endless bool, slot string) error {
+	if err := storage.NewJSONDungeonStorage().DeleteSave(slot); err != nil {
+		return err
+	}
+	cfgGame, err := storage.LoadDungeonConfig(storage.DungeonConfigFile)
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
-	uc.dungeon = storage.GenerateDungeonFromConfig(1, cfgGame, nil)
+	cfgGame.EndlessMode = endless
+	seed := uc.seed
+	if seed == 0 {
+		seed = common.NewSeed()
+	}
+	uc.dungeon = storage.GenerateDungeonFromConfig(1, cfgGame, nil, common.NewRNG(seed))
+	uc.dungeon.AddEventData(fmt.Sprintf("New game, seed %d", seed))
 	character, ok := uc.dungeon.Player.(*unit.Character)
 	if !ok {
 		panic("player is not of type *unit.Character")
 	}
 	uc.character = character
+	uc.floors = dungeon.Floors{}
 	uc.cfg = cfgGame
+	uc.slot = slot
+	uc.playback = false
+	uc.replay.Start(seed, endless, nil)
 	uc.view.GameWindow.Clear()
+	return nil
 }
 
 // RenderLeaderBord displays the game's leaderboard statistics in MainWindow.
 // It retrieves the leaderboard data, renders the statistics window, waits for user input,
-// and then returns to the main window.
+// and then returns to the main window. E switches between the campaign and the endless leaderboards.
 func (uc *PlayerActionUseCase) RenderLeaderBord() {
-	stats := storage.GetLeaderboardSlice()
-	uc.view.RenderStatisticWindow(stats)
-	uc.view.MainWindow.GetChar()
+	endless := false
+	for {
+		uc.view.RenderStatisticWindow(storage.GetLeaderboardSlice(endless), endless)
+		if key := uc.view.MainWindow.GetChar(); key != 'e' && key != 'E' {
+			break
+		}
+		endless = !endless
+	}
 	uc.view.RenderMainWindow()
 }
+
+// SaveSlots returns the list of existing save slots, most recent first.
+func (uc *PlayerActionUseCase) SaveSlots() []common.SaveSlotInfo {
+	slots, err := storage.NewJSONDungeonStorage().ListSaveSlots()
+	if err != nil {
+		return nil
+	}
+	return slots
+}
+
+// RenderSaveSlots displays the save slot picker in MainWindow,
+// either to load a slot or to choose the slot a new game overwrites.
+func (uc *PlayerActionUseCase) RenderSaveSlots(slots []common.SaveSlotInfo, overwrite bool) {
+	uc.view.RenderSaveSlotsWindow(slots, overwrite)
+}
@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/adapters/secondary/storage"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// ReplayRecorder collects the player actions of the current run and writes them to the replay file.
+// It's owned by PlayerActionUseCase and shared with InventoryActionUseCase.
+type ReplayRecorder struct {
+	data      storage.ReplayData
+	path      string
+	recording bool
+}
+
+// Start begins recording of a new run.
+// start is the loaded game state, or nil if the run is a new game with the given seed and mode.
+func (r *ReplayRecorder) Start(seed int64, endless bool, start *storage.DungeonData) {
+	now := time.Now()
+	r.data = storage.ReplayData{
+		Seed:      seed,
+		Endless:   endless,
+		Start:     start,
+		StartedAt: now,
+	}
+	r.path = storage.ReplayPath(now, seed)
+	r.recording = true
+}
+
+// Stop turns recording off, e.g. while a replay is played back.
+func (r *ReplayRecorder) Stop() {
+	r.recording = false
+}
+
+// RecordMove adds the player's move in the direction.
+func (r *ReplayRecorder) RecordMove(dir unit.Direction) {
+	if !r.recording {
+		return
+	}
+	r.data.Actions = append(r.data.Actions, storage.ReplayActionData{
+		Kind:      storage.ReplayMove,
+		Direction: int(dir),
+	})
+}
+
+// RecordSearch adds the player's search turn.
+func (r *ReplayRecorder) RecordSearch() {
+	if !r.recording {
+		return
+	}
+	r.data.Actions = append(r.data.Actions, storage.ReplayActionData{Kind: storage.ReplaySearch})
+}
+
+// RecordSelect adds the selection of the inventory item.
+func (r *ReplayRecorder) RecordSelect(itemType item.Type, num int) {
+	if !r.recording {
+		return
+	}
+	r.data.Actions = append(r.data.Actions, storage.ReplayActionData{
+		Kind:     storage.ReplaySelect,
+		ItemType: int(itemType),
+		Index:    num,
+	})
+}
+
+// Save writes the recorded run to its replay file.
+func (r *ReplayRecorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+	if err := storage.SaveReplay(r.path, r.data); err != nil {
+		return fmt.Errorf("replay save error: %w", err)
+	}
+	return nil
+}
+
+// ReplayDelays defines the playback speeds as delays between two actions, from slowest to fastest.
+var ReplayDelays = []time.Duration{
+	800 * time.Millisecond,
+	400 * time.Millisecond,
+	200 * time.Millisecond,
+	100 * time.Millisecond,
+	50 * time.Millisecond,
+}
+
+// ReplayPlaybackUseCase plays a recorded run turn by turn through the usual use cases and view.
+type ReplayPlaybackUseCase struct {
+	playerActionUC    *PlayerActionUseCase
+	inventoryActionUC *InventoryActionUseCase
+	replay            *storage.ReplayData
+	next              int       // index of the next action
+	speed             int       // index in ReplayDelays
+	paused            bool      // playback waits for the step command
+	lastStep          time.Time // time of the last played action
+}
+
+// NewReplayPlayback creates a playback use case working through the given game use cases.
+func NewReplayPlayback(playerActionUC *PlayerActionUseCase, inventoryActionUC *InventoryActionUseCase) *ReplayPlaybackUseCase {
+	return &ReplayPlaybackUseCase{
+		playerActionUC:    playerActionUC,
+		inventoryActionUC: inventoryActionUC,
+		speed:             2,
+	}
+}
+
+// Load reads the replay file and restores the starting state of the recorded run.
+// An empty path means the most recent replay.
+func (uc *ReplayPlaybackUseCase) Load(path string) error {
+	if path == "" {
+		latest, err := storage.LatestReplay()
+		if err != nil {
+			return fmt.Errorf("replay load error: %w", err)
+		}
+		path = latest
+	}
+	replay, err := storage.LoadReplay(path)
+	if err != nil {
+		return fmt.Errorf("replay load error: %w", err)
+	}
+	if err := uc.playerActionUC.StartPlayback(replay); err != nil {
+		return err
+	}
+	uc.replay = replay
+	uc.next = 0
+	uc.paused = false
+	uc.lastStep = time.Now()
+	uc.renderStatus()
+	return nil
+}
+
+// Tick plays the next action if the playback isn't paused and the delay of the current speed has passed.
+func (uc *ReplayPlaybackUseCase) Tick() ActionResult {
+	if uc.paused || time.Since(uc.lastStep) < ReplayDelays[uc.speed] {
+		return ContinueGame
+	}
+	return uc.Step()
+}
+
+// Step plays exactly one action of the replay.
+func (uc *ReplayPlaybackUseCase) Step() ActionResult {
+	if uc.Finished() {
+		uc.paused = true
+		uc.renderStatus()
+		return ContinueGame
+	}
+	action := uc.replay.Actions[uc.next]
+	uc.next++
+	uc.lastStep = time.Now()
+
+	result := ContinueGame
+	switch action.Kind {
+	case storage.ReplayMove:
+		result = uc.playerActionUC.Execute(unit.Direction(action.Direction))
+	case storage.ReplaySearch:
+		result = uc.playerActionUC.Search()
+	case storage.ReplaySelect:
+		uc.inventoryActionUC.SelectItem(item.Type(action.ItemType), action.Index)
+		uc.playerActionUC.RenderInitial()
+	}
+	if result == NextLevel || result == PreviousLevel {
+		uc.playerActionUC.RenderInitial()
+	}
+	if result == ContinueGame || result == NextLevel || result == PreviousLevel {
+		uc.renderStatus()
+	}
+	return result
+}
+
+// TogglePause pauses or resumes the playback.
+func (uc *ReplayPlaybackUseCase) TogglePause() {
+	uc.paused = !uc.paused
+	uc.renderStatus()
+}
+
+// Faster increases the playback speed.
+func (uc *ReplayPlaybackUseCase) Faster() {
+	if uc.speed < len(ReplayDelays)-1 {
+		uc.speed++
+	}
+	uc.renderStatus()
+}
+
+// Slower decreases the playback speed.
+func (uc *ReplayPlaybackUseCase) Slower() {
+	if uc.speed > 0 {
+		uc.speed--
+	}
+	uc.renderStatus()
+}
+
+// Finished checks if all actions of the replay have been played.
+func (uc *ReplayPlaybackUseCase) Finished() bool {
+	return uc.replay == nil || uc.next >= len(uc.replay.Actions)
+}
+
+// renderStatus shows the playback position, speed and state.
+func (uc *ReplayPlaybackUseCase) renderStatus() {
+	state := "playing"
+	if uc.Finished() {
+		state = "end of replay"
+	} else if uc.paused {
+		state = "paused"
+	}
+	total := 0
+	if uc.replay != nil {
+		total = len(uc.replay.Actions)
+	}
+	uc.playerActionUC.view.RenderReplayStatus(fmt.Sprintf("REPLAY %d/%d  speed %d/%d  %s",
+		uc.next, total, uc.speed+1, len(ReplayDelays), state))
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Colour is the colour an entity is drawn with on the game screen.
+type Colour int
+
+// Colours of the entities on the black background of the game map.
+const (
+	ColourWhite Colour = iota
+	ColourRed
+	ColourGreen
+	ColourYellow
+	ColourBlue
+)
+
+// ErrUnknownColour is returned when there's no colour with the requested name.
+var ErrUnknownColour = errors.New("unknown colour")
+
+// colourNames contains the colours by their config names
+var colourNames = map[string]Colour{
+	"white":  ColourWhite,
+	"red":    ColourRed,
+	"green":  ColourGreen,
+	"yellow": ColourYellow,
+	"blue":   ColourBlue,
+}
+
+// ColourByName returns the colour with the given config name.
+func ColourByName(name string) (Colour, error) {
+	colour, ok := colourNames[name]
+	if !ok {
+		return ColourWhite, fmt.Errorf("%w: %q", ErrUnknownColour, name)
+	}
+	return colour, nil
+}
+
+// Name returns the config name of the colour.
+func (c Colour) Name() string {
+	for name, colour := range colourNames {
+		if colour == c {
+			return name
+		}
+	}
+	return ""
+}
+
+// ColourNames returns sorted names of all colours.
+func ColourNames() []string {
+	names := make([]string, 0, len(colourNames))
+	for name := range colourNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
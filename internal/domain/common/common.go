@@ -1,5 +1,7 @@
 package common
 
+import "time"
+
 // Coords represents a 2D coordinate position with X and Y values as unsigned integers.
 type Coords struct {
 	X, Y int
@@ -16,15 +18,19 @@ type TileType int
 // TileType represents different types of dungeon tiles.
 // Used for game logic and rendering differentiation.
 const (
-	UnknownTile  TileType = iota // Undiscovered or invalid tile
-	FloorTile                    // Walkable floor space
-	WallTile                     // Impassable wall
-	ItemTile                     // Contains an item (weapon, food, etc)
-	EnemyTile                    // Contains an enemy unit
-	FinishTile                   // Level exit/win condition
-	DoorTile                     // Door/passage between areas
-	PlayerTile                   // Current player position
-	CorridorTile                 // Connecting passage between rooms
+	UnknownTile    TileType = iota // Undiscovered or invalid tile
+	FloorTile                      // Walkable floor space
+	WallTile                       // Impassable wall
+	ItemTile                       // Contains an item (weapon, food, etc)
+	EnemyTile                      // Contains an enemy unit
+	FinishTile                     // Level exit/win condition
+	DoorTile                       // Door/passage between areas
+	PlayerTile                     // Current player position
+	CorridorTile                   // Connecting passage between rooms
+	LockedDoorTile                 // Door which can be opened only with its key
+	TrapTile                       // Trap which has been triggered or detected
+	StairsUpTile                   // Stairs up to the previous level
+	ArtifactTile                   // The artifact to retrieve on the last level
 )
 
 // Stats contains player statistics throughout the game
@@ -39,4 +45,16 @@ type Stats struct {
 	HitsMade          int
 	HitsMissed        int
 	CellsPassed       int
+	Endless           bool // the run is in the endless mode, its stats go to the endless leaderboard
+}
+
+// SaveSlotInfo is a short summary of one saved game, shown in the slot picker.
+type SaveSlotInfo struct {
+	Name      string    // Slot name (used as the save file name)
+	Level     int       // Dungeon level the game was saved on
+	Treasure  int       // Treasure collected at the moment of saving
+	Health    int       // Character's health at the moment of saving
+	MaxHealth int       // Character's max health at the moment of saving
+	Seed      int64     // Seed of the saved game
+	SavedAt   time.Time // Time of the last save into this slot
 }
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"math/rand"
+)
+
+// maxNewSeed limits generated seeds, so they're short enough to be typed in a bug report
+const maxNewSeed = 1_000_000_000
+
+// RNG is the seeded pseudorandom generator of a game session.
+// All game randomness goes through it, so the same seed and the same player input give the same game.
+// It counts the numbers drawn from the source, so its exact state can be saved and restored.
+type RNG struct {
+	*rand.Rand
+	src  *countingSource
+	seed int64
+}
+
+// countingSource wraps a rand.Source64 and counts the values drawn from it.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+// Int63 implements rand.Source.
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+// Uint64 implements rand.Source64.
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+// Seed implements rand.Source.
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// NewSeed returns a random non-zero seed, for games started without a seed.
+func NewSeed() int64 {
+	return rand.Int63n(maxNewSeed-1) + 1
+}
+
+// NewRNG creates a generator seeded with the given seed.
+func NewRNG(seed int64) *RNG {
+	src := &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+	return &RNG{
+		Rand: rand.New(src),
+		src:  src,
+		seed: seed,
+	}
+}
+
+// RestoreRNG creates a generator with the given seed and skips the given number of draws,
+// so it continues exactly where the saved generator stopped.
+func RestoreRNG(seed int64, draws uint64) *RNG {
+	r := NewRNG(seed)
+	for i := uint64(0); i < draws; i++ {
+		r.src.Int63()
+	}
+	return r
+}
+
+// Seed returns the seed the generator was created with.
+func (r *RNG) Seed() int64 {
+	return r.seed
+}
+
+// Draws returns the number of values drawn from the source since seeding.
+func (r *RNG) Draws() uint64 {
+	return r.src.draws
+}
@@ -0,0 +1,337 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Legend of the ASCII maps. Items and enemies use the same letters as on the game screen.
+const (
+	MapWallHorizontal = '-' // top and bottom walls of a room, including the corners
+	MapWallVertical   = '|' // left and right walls of a room
+	MapDoor           = '+' // door in a room wall
+	MapCorridor       = '#' // corridor tile
+	MapFloor          = '.' // room floor
+	MapStart          = '@' // start position of the player, inside a room
+	MapExit           = 'E' // exit to the next level, inside a room
+)
+
+// MapItemSymbols and MapEnemySymbols are the letters of items and enemies which can be placed on the room floor.
+// Every capital letter except the exit can be the glyph of an enemy kind of the config.
+const (
+	MapItemSymbols  = "feswa"                     // food, elixir, scroll, weapon, armor
+	MapEnemySymbols = "ABCDFGHIJKLMNOPQRSTUVWXYZ" // glyphs of enemy kinds
+)
+
+// mapFreeTiles is the number of free floor tiles a map must leave for the stairs up, the artifact and the boss,
+// which the game puts next to the start and the exit.
+const mapFreeTiles = 3
+
+// ErrInvalidMap is returned when an ASCII map can't be turned into a level.
+var ErrInvalidMap = errors.New("invalid ASCII map")
+
+// MapMarker is an item or an enemy placed on the map. The caller creates the actual entity by its symbol.
+type MapMarker struct {
+	common.Coords
+	Symbol rune
+}
+
+// ASCIIMap is a hand-authored level: the layout with the start room and the exit, and the entities to create.
+type ASCIIMap struct {
+	Layout  Dungeon       // rooms, passages and exit; unused rooms have zero size
+	Start   common.Coords // start position of the player
+	Items   []MapMarker
+	Enemies []MapMarker
+}
+
+// NewLayout returns a copy of the map layout which can be changed by the game without touching the map.
+func (m *ASCIIMap) NewLayout() Dungeon {
+	layout := m.Layout
+	for i := range layout.Rooms {
+		layout.Rooms[i].Doors = append([]Door(nil), m.Layout.Rooms[i].Doors...)
+	}
+	layout.Passages = make([]Passage, len(m.Layout.Passages))
+	for i, passage := range m.Layout.Passages {
+		layout.Passages[i] = Passage{Path: append([]Corridor(nil), passage.Path...), Cave: passage.Cave}
+	}
+	return layout
+}
+
+// ParseASCIIMap builds a level from the text of an ASCII map.
+// Rooms are rectangles of walls with doors, corridors are groups of connected corridor tiles.
+// The map must fit the game map, contain from 1 to common.MaxRoomCount rooms, one start and one exit,
+// the exit must be reachable from the start and the reachable rooms must leave mapFreeTiles tiles of free floor.
+func ParseASCIIMap(text string) (*ASCIIMap, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > common.MapHeight {
+		return nil, fmt.Errorf("%w: %d rows, at most %d allowed", ErrInvalidMap, len(lines), common.MapHeight)
+	}
+
+	var grid mapGrid
+	for y, line := range lines {
+		row := []rune(strings.TrimRight(line, " \t"))
+		if len(row) > common.MapWidth {
+			return nil, fmt.Errorf("%w: row %d has %d columns, at most %d allowed", ErrInvalidMap, y+1, len(row), common.MapWidth)
+		}
+		for x := range grid[y] {
+			grid[y][x] = ' '
+			if x < len(row) {
+				grid[y][x] = row[x]
+			}
+		}
+	}
+	for y := len(lines); y < common.MapHeight; y++ {
+		for x := range grid[y] {
+			grid[y][x] = ' '
+		}
+	}
+
+	m := &ASCIIMap{}
+	rooms, err := grid.rooms()
+	if err != nil {
+		return nil, err
+	}
+	copy(m.Layout.Rooms[:], rooms)
+	m.Layout.Passages = grid.passages()
+
+	startFound, exitFound := false, false
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			c := common.Coords{X: x, Y: y}
+			symbol := grid[y][x]
+			room := roomWithFloor(rooms, c)
+			if room < 0 {
+				if symbol != ' ' && symbol != MapCorridor && !inRoomWalls(rooms, c) {
+					return nil, fmt.Errorf("%w: unexpected %q at row %d, column %d", ErrInvalidMap, symbol, y+1, x+1)
+				}
+				continue
+			}
+			switch {
+			case symbol == MapFloor:
+			case symbol == MapStart:
+				if startFound {
+					return nil, fmt.Errorf("%w: more than one start", ErrInvalidMap)
+				}
+				startFound = true
+				m.Start = c
+				m.Layout.Rooms[room].Type = RoomStart
+				m.Layout.Rooms[room].Visited = true
+			case symbol == MapExit:
+				if exitFound {
+					return nil, fmt.Errorf("%w: more than one exit", ErrInvalidMap)
+				}
+				exitFound = true
+				m.Layout.Exit = c
+				if m.Layout.Rooms[room].Type != RoomStart {
+					m.Layout.Rooms[room].Type = RoomEnd
+				}
+			case strings.ContainsRune(MapItemSymbols, symbol):
+				m.Items = append(m.Items, MapMarker{Coords: c, Symbol: symbol})
+			default:
+				m.Enemies = append(m.Enemies, MapMarker{Coords: c, Symbol: symbol})
+			}
+		}
+	}
+	if !startFound || !exitFound {
+		return nil, fmt.Errorf("%w: the map needs a start %q and an exit %q", ErrInvalidMap, MapStart, MapExit)
+	}
+	reachable := m.Layout.reachableTiles(m.Start, nil)
+	if !reachable[m.Layout.Exit] {
+		return nil, fmt.Errorf("%w: the exit can't be reached from the start", ErrInvalidMap)
+	}
+	taken := map[common.Coords]bool{m.Start: true, m.Layout.Exit: true}
+	for _, marker := range append(append([]MapMarker(nil), m.Items...), m.Enemies...) {
+		taken[marker.Coords] = true
+	}
+	free := 0
+	for c := range reachable {
+		if m.Layout.onAnyFloor(c) && !taken[c] {
+			free++
+		}
+	}
+	if free < mapFreeTiles {
+		return nil, fmt.Errorf("%w: %d free floor tiles can be reached, at least %d needed", ErrInvalidMap, free, mapFreeTiles)
+	}
+	return m, nil
+}
+
+// mapGrid holds the symbols of the ASCII map, padded by spaces to the game map size
+type mapGrid [common.MapHeight][common.MapWidth]rune
+
+// at returns the symbol at the coordinates, a space outside of the map.
+func (g *mapGrid) at(x, y int) rune {
+	if x < 0 || y < 0 || x >= common.MapWidth || y >= common.MapHeight {
+		return ' '
+	}
+	return g[y][x]
+}
+
+// isFloorSymbol checks if the symbol can be placed on the room floor.
+func isFloorSymbol(symbol rune) bool {
+	return symbol == MapFloor || symbol == MapStart || symbol == MapExit ||
+		strings.ContainsRune(MapItemSymbols+MapEnemySymbols, symbol)
+}
+
+// rooms finds all rooms of the map in row order, starting from their upper left corners.
+func (g *mapGrid) rooms() ([]Room, error) {
+	var rooms []Room
+	var used [common.MapHeight][common.MapWidth]bool
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			if used[y][x] || g[y][x] != MapWallHorizontal {
+				continue
+			}
+			room, err := g.roomAt(x, y)
+			if err != nil {
+				return nil, err
+			}
+			for ry := room.Y; ry < room.Y+room.Height; ry++ {
+				for rx := room.X; rx < room.X+room.Width; rx++ {
+					used[ry][rx] = true
+				}
+			}
+			rooms = append(rooms, room)
+		}
+	}
+	if len(rooms) == 0 || len(rooms) > common.MaxRoomCount {
+		return nil, fmt.Errorf("%w: %d rooms, from 1 to %d allowed", ErrInvalidMap, len(rooms), common.MaxRoomCount)
+	}
+	return rooms, nil
+}
+
+// roomAt reads the room with the upper left corner at the coordinates and checks its walls.
+func (g *mapGrid) roomAt(x0, y0 int) (Room, error) {
+	x1 := x0
+	for g.at(x1+1, y0) == MapWallHorizontal || g.at(x1+1, y0) == MapDoor {
+		x1++
+	}
+	y1 := y0 + 1
+	for g.at(x0, y1) == MapWallVertical || g.at(x0, y1) == MapDoor {
+		y1++
+	}
+	room := Room{
+		Coords: common.Coords{X: x0, Y: y0},
+		Size:   common.Size{Width: x1 - x0 + 1, Height: y1 - y0 + 1},
+	}
+	if room.Width < 3 || room.Height < 3 {
+		return room, fmt.Errorf("%w: broken room wall at row %d, column %d", ErrInvalidMap, y0+1, x0+1)
+	}
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			c := common.Coords{X: x, Y: y}
+			symbol := g[y][x]
+			corner := (x == x0 || x == x1) && (y == y0 || y == y1)
+			switch {
+			case corner:
+				if symbol != MapWallHorizontal {
+					return room, fmt.Errorf("%w: room corner expected at row %d, column %d", ErrInvalidMap, y+1, x+1)
+				}
+			case symbol == MapDoor && IsCoordInWall(c, room):
+				room.Doors = append(room.Doors, Door{Coords: c})
+			case y == y0 || y == y1:
+				if symbol != MapWallHorizontal {
+					return room, fmt.Errorf("%w: horizontal wall expected at row %d, column %d", ErrInvalidMap, y+1, x+1)
+				}
+			case x == x0 || x == x1:
+				if symbol != MapWallVertical {
+					return room, fmt.Errorf("%w: vertical wall expected at row %d, column %d", ErrInvalidMap, y+1, x+1)
+				}
+			case !isFloorSymbol(symbol):
+				return room, fmt.Errorf("%w: room floor expected at row %d, column %d", ErrInvalidMap, y+1, x+1)
+			}
+		}
+	}
+	return room, nil
+}
+
+// passages groups connected corridor tiles into passages.
+// Every passage is stored as horizontal corridors of two or more tiles and vertical corridors of the rest tiles.
+func (g *mapGrid) passages() []Passage {
+	var passages []Passage
+	var seen [common.MapHeight][common.MapWidth]bool
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			if seen[y][x] || g[y][x] != MapCorridor {
+				continue
+			}
+			var region [common.MapHeight][common.MapWidth]bool
+			stack := []common.Coords{{X: x, Y: y}}
+			seen[y][x] = true
+			for len(stack) > 0 {
+				c := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				region[c.Y][c.X] = true
+				for _, n := range neighbours(c) {
+					if g.at(n.X, n.Y) == MapCorridor && !seen[n.Y][n.X] {
+						seen[n.Y][n.X] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+			passages = append(passages, regionPassage(&region))
+		}
+	}
+	return passages
+}
+
+// regionPassage turns the corridor tiles of the region into corridors.
+func regionPassage(region *[common.MapHeight][common.MapWidth]bool) Passage {
+	var passage Passage
+	var single [common.MapHeight][common.MapWidth]bool
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			if !region[y][x] || (x > 0 && region[y][x-1]) {
+				continue
+			}
+			end := x
+			for end+1 < common.MapWidth && region[y][end+1] {
+				end++
+			}
+			if end > x {
+				addCorridor(&passage, x, y, end, y)
+			} else {
+				single[y][x] = true
+			}
+		}
+	}
+	for x := 0; x < common.MapWidth; x++ {
+		for y := 0; y < common.MapHeight; y++ {
+			if !single[y][x] || (y > 0 && single[y-1][x]) {
+				continue
+			}
+			end := y
+			for end+1 < common.MapHeight && single[end+1][x] {
+				end++
+			}
+			addCorridor(&passage, x, y, x, end)
+		}
+	}
+	return passage
+}
+
+// inRoomWalls checks if the coordinates are on the walls of a room.
+func inRoomWalls(rooms []Room, c common.Coords) bool {
+	for _, room := range rooms {
+		if IsCoordInWall(c, room) {
+			return true
+		}
+	}
+	return false
+}
+
+// roomWithFloor returns the index of the room which floor contains the coordinates, -1 if there's no such room.
+func roomWithFloor(rooms []Room, c common.Coords) int {
+	for i, room := range rooms {
+		if IsCoordInRoom(c, room) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,254 @@
+package dungeon
+
+import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Minimal size of BSP leaf: the smallest room with one free tile around it for corridors
+const (
+	minLeafWidth  = common.MinRoomWidth + 2
+	minLeafHeight = common.MinRoomHeight + 2
+)
+
+// BSPGenerator splits the map by binary space partitioning until there's a leaf for every room,
+// places a room inside every leaf and connects sibling subtrees through their split lines.
+// LayoutOptions.ExtraConnections is the share of neighbouring leaves, which aren't connected yet, joined by loops.
+type BSPGenerator struct{}
+
+// bspNode is a rectangular area of the map. Leaves hold a room index, other nodes are split in two.
+type bspNode struct {
+	common.Coords             // upper left corner of the area
+	common.Size               // area size
+	vertical      bool        // split line is vertical (left and right children)
+	split         int         // coordinate of the split line, the first column or row of the second child
+	children      [2]*bspNode // first child is the left or the upper one
+	room          int         // index of the room for leaves
+}
+
+// Generate creates a new BSP dungeon.
+func (BSPGenerator) Generate(rng *common.RNG, opts LayoutOptions) Dungeon {
+	dungeon := Dungeon{RNG: rng}
+	root := &bspNode{Size: common.Size{Width: common.MapWidth, Height: common.MapHeight}}
+	leaves := []*bspNode{root}
+	for len(leaves) < common.MaxRoomCount {
+		i := largestSplittableLeaf(leaves)
+		leaf := leaves[i]
+		leaf.splitArea(rng)
+		leaves = append(leaves[:i], append(leaf.children[:], leaves[i+1:]...)...)
+	}
+
+	for i, leaf := range leaves {
+		leaf.room = i
+		dungeon.Rooms[i] = generateRoomInLeaf(rng, leaf)
+	}
+	tree := make(map[roomPair]bool, len(leaves))
+	connectBSP(rng, root, dungeon.Rooms[:], &dungeon, tree)
+	addBSPLoops(rng, leaves, &dungeon, tree, opts.ExtraConnections)
+	addDeadEnds(rng, &dungeon, opts.DeadEnds)
+	setStartAndEnd(rng, &dungeon)
+
+	return dungeon
+}
+
+// largestSplittableLeaf returns the index of the largest leaf which is big enough to be split.
+// The map always fits common.MaxRoomCount minimal leaves, so there is such a leaf while more rooms are needed.
+func largestSplittableLeaf(leaves []*bspNode) int {
+	best := -1
+	for i, leaf := range leaves {
+		if !leaf.canSplitVertically() && !leaf.canSplitHorizontally() {
+			continue
+		}
+		if best == -1 || leaf.Width*leaf.Height > leaves[best].Width*leaves[best].Height {
+			best = i
+		}
+	}
+	return best
+}
+
+func (n *bspNode) canSplitVertically() bool {
+	return n.Width >= 2*minLeafWidth
+}
+
+func (n *bspNode) canSplitHorizontally() bool {
+	return n.Height >= 2*minLeafHeight
+}
+
+// splitArea divides the node in two children at a random position.
+// Wide areas are split vertically more likely; a console cell is about twice as high as wide.
+func (n *bspNode) splitArea(rng *common.RNG) {
+	switch {
+	case !n.canSplitHorizontally():
+		n.vertical = true
+	case !n.canSplitVertically():
+		n.vertical = false
+	default:
+		n.vertical = rng.Intn(n.Width+2*n.Height) < n.Width
+	}
+
+	first := &bspNode{Coords: n.Coords, Size: n.Size}
+	second := &bspNode{Coords: n.Coords, Size: n.Size}
+	if n.vertical {
+		n.split = n.X + common.RandomInRange(rng, minLeafWidth, n.Width-minLeafWidth)
+		first.Width = n.split - n.X
+		second.X = n.split
+		second.Width = n.Width - first.Width
+	} else {
+		n.split = n.Y + common.RandomInRange(rng, minLeafHeight, n.Height-minLeafHeight)
+		first.Height = n.split - n.Y
+		second.Y = n.split
+		second.Height = n.Height - first.Height
+	}
+	n.children = [2]*bspNode{first, second}
+}
+
+// isLeaf checks if the node isn't split.
+func (n *bspNode) isLeaf() bool {
+	return n.children[0] == nil
+}
+
+// leaves returns all leaves of the subtree.
+func (n *bspNode) leaves() []*bspNode {
+	if n.isLeaf() {
+		return []*bspNode{n}
+	}
+	return append(n.children[0].leaves(), n.children[1].leaves()...)
+}
+
+// generateRoomInLeaf creates a random room inside the leaf, keeping one free tile to the leaf borders.
+func generateRoomInLeaf(rng *common.RNG, leaf *bspNode) Room {
+	var room Room
+	room.Width = common.RandomInRange(rng, common.MinRoomWidth, min(leaf.Width-2, common.MaxRoomWidth))
+	room.Height = common.RandomInRange(rng, common.MinRoomHeight, leaf.Height-2)
+	room.X = leaf.X + 1 + rng.Intn(leaf.Width-room.Width-1)
+	room.Y = leaf.Y + 1 + rng.Intn(leaf.Height-room.Height-1)
+	return room
+}
+
+// connectBSP connects the two subtrees of every split node by a passage between their rooms
+// which are adjacent to the split line.
+// The passage leaves the first room, turns on the split line, which is free from rooms, and enters the second room,
+// so it never crosses other rooms. The connected pairs of rooms are added to tree.
+func connectBSP(rng *common.RNG, node *bspNode, rooms []Room, dg *Dungeon, tree map[roomPair]bool) {
+	if node.isLeaf() {
+		return
+	}
+	connectBSP(rng, node.children[0], rooms, dg, tree)
+	connectBSP(rng, node.children[1], rooms, dg, tree)
+
+	src := adjacentLeaf(rng, node.children[0].leaves(), func(l *bspNode) bool {
+		if node.vertical {
+			return l.X+l.Width == node.split
+		}
+		return l.Y+l.Height == node.split
+	})
+	dst := adjacentLeaf(rng, node.children[1].leaves(), func(l *bspNode) bool {
+		if node.vertical {
+			return l.X == node.split
+		}
+		return l.Y == node.split
+	})
+
+	var passage Passage
+	if node.vertical {
+		generateSplitHorizontalCorridor(rng, &rooms[src.room], &rooms[dst.room], node.split, &passage)
+	} else {
+		generateSplitVerticalCorridor(rng, &rooms[src.room], &rooms[dst.room], node.split, &passage)
+	}
+	dg.Passages = append(dg.Passages, passage)
+	tree[newRoomPair(src.room, dst.room)] = true
+}
+
+// addBSPLoops connects the share ratio of neighbouring leaves which aren't connected yet, so the level gets loops.
+// The passage turns on the border of the leaves, which is free from rooms like a split line.
+// Passages which would overlap other passages are skipped.
+func addBSPLoops(rng *common.RNG, leaves []*bspNode, dg *Dungeon, tree map[roomPair]bool, ratio float64) {
+	var candidates [][2]*bspNode
+	for _, a := range leaves {
+		for _, b := range leaves {
+			if (a.leftNeighbourOf(b) || a.upperNeighbourOf(b)) && !tree[newRoomPair(a.room, b.room)] {
+				candidates = append(candidates, [2]*bspNode{a, b})
+			}
+		}
+	}
+	count := int(ratio*float64(len(candidates)) + 0.5)
+	if count == 0 {
+		return
+	}
+	for _, i := range rng.Perm(len(candidates)) {
+		if count == 0 {
+			return
+		}
+		first, second := candidates[i][0], candidates[i][1]
+		src, dst := &dg.Rooms[first.room], &dg.Rooms[second.room]
+		srcDoors, dstDoors := len(src.Doors), len(dst.Doors)
+		var passage Passage
+		if first.leftNeighbourOf(second) {
+			generateSplitHorizontalCorridor(rng, src, dst, second.X, &passage)
+		} else {
+			generateSplitVerticalCorridor(rng, src, dst, second.Y, &passage)
+		}
+		if !dg.passageFits(passage, nil) {
+			src.Doors, dst.Doors = src.Doors[:srcDoors], dst.Doors[:dstDoors]
+			continue
+		}
+		dg.Passages = append(dg.Passages, passage)
+		count--
+	}
+}
+
+// leftNeighbourOf checks if the right border of the leaf shares a segment with the left border of the other leaf.
+func (n *bspNode) leftNeighbourOf(other *bspNode) bool {
+	return n.X+n.Width == other.X && n.Y < other.Y+other.Height && other.Y < n.Y+n.Height
+}
+
+// upperNeighbourOf checks if the bottom border of the leaf shares a segment with the top border of the other leaf.
+func (n *bspNode) upperNeighbourOf(other *bspNode) bool {
+	return n.Y+n.Height == other.Y && n.X < other.X+other.Width && other.X < n.X+n.Width
+}
+
+// adjacentLeaf returns a random leaf touching the split line.
+func adjacentLeaf(rng *common.RNG, leaves []*bspNode, touches func(*bspNode) bool) *bspNode {
+	var candidates []*bspNode
+	for _, leaf := range leaves {
+		if touches(leaf) {
+			candidates = append(candidates, leaf)
+		}
+	}
+	return candidates[rng.Intn(len(candidates))]
+}
+
+// generateSplitHorizontalCorridor connects the left room with the right one, turning on the splitX column.
+func generateSplitHorizontalCorridor(rng *common.RNG, srcRoom *Room, destRoom *Room, splitX int, pass *Passage) {
+	srcWall, dstWall := srcRoom.X+srcRoom.Width-1, destRoom.X
+	srcY := common.RandomInRange(rng, srcRoom.Y+1, srcRoom.Y+srcRoom.Height-2)
+	dstY := common.RandomInRange(rng, destRoom.Y+1, destRoom.Y+destRoom.Height-2)
+
+	srcRoom.Doors = append(srcRoom.Doors, Door{Coords: common.Coords{X: srcWall, Y: srcY}})
+	destRoom.Doors = append(destRoom.Doors, Door{Coords: common.Coords{X: dstWall, Y: dstY}})
+
+	if srcY == dstY {
+		addCorridor(pass, srcWall+1, srcY, dstWall-1, dstY)
+		return
+	}
+	addCorridor(pass, srcWall+1, srcY, splitX, srcY)
+	addCorridor(pass, splitX, srcY, splitX, dstY)
+	addCorridor(pass, splitX, dstY, dstWall-1, dstY)
+}
+
+// generateSplitVerticalCorridor connects the upper room with the lower one, turning on the splitY row.
+func generateSplitVerticalCorridor(rng *common.RNG, srcRoom *Room, destRoom *Room, splitY int, pass *Passage) {
+	srcWall, dstWall := srcRoom.Y+srcRoom.Height-1, destRoom.Y
+	srcX := common.RandomInRange(rng, srcRoom.X+1, srcRoom.X+srcRoom.Width-2)
+	dstX := common.RandomInRange(rng, destRoom.X+1, destRoom.X+destRoom.Width-2)
+
+	srcRoom.Doors = append(srcRoom.Doors, Door{Coords: common.Coords{X: srcX, Y: srcWall}})
+	destRoom.Doors = append(destRoom.Doors, Door{Coords: common.Coords{X: dstX, Y: dstWall}})
+
+	if srcX == dstX {
+		addCorridor(pass, srcX, srcWall+1, dstX, dstWall-1)
+		return
+	}
+	addCorridor(pass, srcX, srcWall+1, srcX, splitY)
+	addCorridor(pass, srcX, splitY, dstX, splitY)
+	addCorridor(pass, dstX, splitY, dstX, dstWall-1)
+}
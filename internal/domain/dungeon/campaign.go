@@ -0,0 +1,70 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Victory is the condition which wins the campaign on its last level
+type Victory int
+
+const (
+	// VictoryReachExit wins by stepping on the exit of the last level
+	VictoryReachExit Victory = iota
+	// VictoryKillBoss wins by defeating the boss of the last level
+	VictoryKillBoss
+	// VictoryRetrieveArtifact wins by leaving the last level through its exit with the artifact
+	VictoryRetrieveArtifact
+)
+
+// ErrUnknownVictory is returned when there's no victory condition with the requested name.
+var ErrUnknownVictory = errors.New("unknown victory condition")
+
+// victoryNames contains the victory conditions by their config names
+var victoryNames = map[string]Victory{
+	"reach_exit":        VictoryReachExit,
+	"kill_boss":         VictoryKillBoss,
+	"retrieve_artifact": VictoryRetrieveArtifact,
+}
+
+// VictoryByName returns the victory condition with the given config name.
+func VictoryByName(name string) (Victory, error) {
+	victory, ok := victoryNames[name]
+	if !ok {
+		return VictoryReachExit, fmt.Errorf("%w: %q", ErrUnknownVictory, name)
+	}
+	return victory, nil
+}
+
+// VictoryNames returns sorted names of all victory conditions.
+func VictoryNames() []string {
+	names := make([]string, 0, len(victoryNames))
+	for name := range victoryNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Won checks if the victory condition is met on the level. Only the last level of the campaign is checked.
+func (d *Dungeon) Won(v Victory) bool {
+	switch v {
+	case VictoryKillBoss:
+		return d.BossDefeated
+	case VictoryRetrieveArtifact:
+		return d.ArtifactTaken && d.IsExit()
+	default:
+		return d.IsExit()
+	}
+}
+
+// TakeArtifact picks up the artifact if the player stands on it. Returns true if the artifact has been taken.
+func (d *Dungeon) TakeArtifact() bool {
+	if d.Artifact == nil || d.PlayerCoords() != *d.Artifact {
+		return false
+	}
+	d.Artifact = nil
+	d.ArtifactTaken = true
+	return true
+}
@@ -0,0 +1,150 @@
+package dungeon
+
+import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Cellular automata parameters of the cave generator
+const (
+	caveChamberMaxWidth = common.MinRoomWidth + 8 // chambers are small to leave space for the caves
+	caveFillPercent     = 45                      // chance of open tile in the initial noise
+	caveSmoothSteps     = 4                       // number of automata generations
+	caveWallNeighbours  = 5                       // tile becomes a wall with this number of wall neighbours or more
+)
+
+// CaveGenerator places small chambers in the 3x3 grid, connects them by DFS corridors
+// and grows cellular automata caves around the corridors.
+// Caves which don't touch any corridor are dropped, so every cave tile is reachable.
+type CaveGenerator struct{}
+
+// Generate creates a new cave dungeon.
+func (CaveGenerator) Generate(rng *common.RNG, opts LayoutOptions) Dungeon {
+	dungeon := Dungeon{RNG: rng}
+	for i := 0; i < common.MaxRoomCount; i++ {
+		dungeon.Rooms[i] = generateSizedRoom(rng, i, caveChamberMaxWidth, common.MaxRoomHeight)
+	}
+	tree := generateRoomConnections(rng, dungeon.Rooms[:], &dungeon)
+	addExtraConnections(rng, &dungeon, tree, opts.ExtraConnections)
+	addDeadEnds(rng, &dungeon, opts.DeadEnds)
+
+	open := growCaves(rng, dungeon.Rooms[:])
+	dungeon.Passages = append(dungeon.Passages, caveRegions(open, dungeon.Passages)...)
+
+	return dungeon
+}
+
+// caveGrid marks open tiles of the map
+type caveGrid [common.MapHeight][common.MapWidth]bool
+
+// growCaves fills the map outside the rooms with random noise and smooths it by cellular automata.
+// Rooms with one tile around them and map borders always stay solid.
+func growCaves(rng *common.RNG, rooms []Room) *caveGrid {
+	var solid caveGrid
+	for _, room := range rooms {
+		for y := room.Y - 1; y <= room.Y+room.Height; y++ {
+			for x := room.X - 1; x <= room.X+room.Width; x++ {
+				if y >= 0 && y < common.MapHeight && x >= 0 && x < common.MapWidth {
+					solid[y][x] = true
+				}
+			}
+		}
+	}
+
+	open := &caveGrid{}
+	for y := 1; y < common.MapHeight-1; y++ {
+		for x := 1; x < common.MapWidth-1; x++ {
+			open[y][x] = !solid[y][x] && rng.Intn(100) < caveFillPercent
+		}
+	}
+
+	for step := 0; step < caveSmoothSteps; step++ {
+		next := &caveGrid{}
+		for y := 1; y < common.MapHeight-1; y++ {
+			for x := 1; x < common.MapWidth-1; x++ {
+				next[y][x] = !solid[y][x] && open.wallNeighbours(x, y) < caveWallNeighbours
+			}
+		}
+		open = next
+	}
+	return open
+}
+
+// wallNeighbours counts closed tiles among 8 neighbours of the tile.
+func (g *caveGrid) wallNeighbours(x, y int) int {
+	walls := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && !g[y+dy][x+dx] {
+				walls++
+			}
+		}
+	}
+	return walls
+}
+
+// caveRegions returns the open tiles connected to the passages as cave passages, one passage per cave.
+// Each cave is stored as horizontal corridors, one for every run of open tiles in a row.
+func caveRegions(open *caveGrid, passages []Passage) []Passage {
+	var corridor caveGrid
+	for _, passage := range passages {
+		for y := 0; y < common.MapHeight; y++ {
+			for x := 0; x < common.MapWidth; x++ {
+				if passage.Contains(common.Coords{X: x, Y: y}) {
+					corridor[y][x] = true
+					open[y][x] = false
+				}
+			}
+		}
+	}
+
+	var caves []Passage
+	var seen caveGrid
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			if !open[y][x] || seen[y][x] {
+				continue
+			}
+			region, connected := floodCave(open, &corridor, &seen, common.Coords{X: x, Y: y})
+			if connected {
+				caves = append(caves, region)
+			}
+		}
+	}
+	return caves
+}
+
+// floodCave collects the cave containing start tile and checks if it touches a corridor.
+func floodCave(open, corridor, seen *caveGrid, start common.Coords) (Passage, bool) {
+	var region caveGrid
+	connected := false
+	stack := []common.Coords{start}
+	seen[start.Y][start.X] = true
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		region[c.Y][c.X] = true
+		for _, n := range []common.Coords{{X: c.X + 1, Y: c.Y}, {X: c.X - 1, Y: c.Y}, {X: c.X, Y: c.Y + 1}, {X: c.X, Y: c.Y - 1}} {
+			if corridor[n.Y][n.X] {
+				connected = true
+			}
+			if open[n.Y][n.X] && !seen[n.Y][n.X] {
+				seen[n.Y][n.X] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	cave := Passage{Cave: true}
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			if region[y][x] && (x == 0 || !region[y][x-1]) {
+				end := x
+				for end+1 < common.MapWidth && region[y][end+1] {
+					end++
+				}
+				addCorridor(&cave, x, y, end, y)
+			}
+		}
+	}
+	return cave, connected
+}
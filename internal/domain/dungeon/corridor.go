@@ -1,14 +1,14 @@
 package dungeon
 
 import (
-	"math/rand"
-
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 )
 
 // Door represents an exit from Room to passage located at a specific coordinate position
 type Door struct {
 	common.Coords
+	Lock   int  // Number of the lock, 0 for an open door
+	Hidden bool // Secret door, looks like a wall until found by searching
 }
 
 // Corridor represents a part of Passage
@@ -16,6 +16,18 @@ type Corridor struct {
 	Begin   common.Coords // Coordinates of corridor begin
 	End     common.Coords // Coordinates of corridor end
 	Visited bool          // Indicates whether the corridor has been visited by a player
+	Hidden  bool          // Secret corridor, can't be seen or walked until found by searching
+}
+
+// roomPair is a pair of room indices, the smaller index goes first
+type roomPair [2]int
+
+// newRoomPair creates a pair of rooms in a canonical order.
+func newRoomPair(a, b int) roomPair {
+	if b < a {
+		a, b = b, a
+	}
+	return roomPair{a, b}
 }
 
 // getNeighbors returns a slice of room indices that are adjacent to the room at the given index.
@@ -41,8 +53,8 @@ func getNeighbors(index int) []int {
 
 // shuffle randomly reorders the elements of the input slice using a permutation.
 // It returns a new slice with the same elements in a random order.
-func shuffle(nums []int) []int {
-	r := rand.Perm(len(nums))
+func shuffle(rng *common.RNG, nums []int) []int {
+	r := rng.Perm(len(nums))
 	shuffled := make([]int, len(nums))
 	for i, v := range r {
 		shuffled[i] = nums[v]
@@ -54,21 +66,23 @@ func shuffle(nums []int) []int {
 // It takes the current room index, a slice of rooms, and a visited tracker as input.
 // The function recursively explores unvisited neighboring rooms, generating passages
 // between connected rooms and marking rooms as plain type during traversal.
+// Connected pairs of rooms are added to tree.
 // It returns a slice of passages created during the room connection process.
-func connectRoomsDFS(index int, rooms []Room, visited []bool) []Passage {
+func connectRoomsDFS(rng *common.RNG, index int, rooms []Room, visited []bool, tree map[roomPair]bool) []Passage {
 	visited[index] = true
 	var passages []Passage
 
-	neighbors := shuffle(getNeighbors(index))
+	neighbors := shuffle(rng, getNeighbors(index))
 	for _, n := range neighbors {
 		if !visited[n] {
 
-			passage := generatePassage(rooms, index, n)
+			passage := generatePassage(rng, rooms, index, n)
 			passages = append(passages, passage)
+			tree[newRoomPair(index, n)] = true
 
 			rooms[n].Type = RoomPlain
 
-			childPassages := connectRoomsDFS(n, rooms, visited)
+			childPassages := connectRoomsDFS(rng, n, rooms, visited, tree)
 			passages = append(passages, childPassages...)
 		}
 	}
@@ -77,24 +91,163 @@ func connectRoomsDFS(index int, rooms []Room, visited []bool) []Passage {
 
 // generateRoomConnections creates connections between rooms in a dungeon by performing a depth-first search traversal.
 // It randomly selects a start and end room, generates passages between rooms, and marks the start and end rooms.
-func generateRoomConnections(rooms []Room, dg *Dungeon) {
+// Returns the pairs of rooms connected by the spanning tree.
+func generateRoomConnections(rng *common.RNG, rooms []Room, dg *Dungeon) map[roomPair]bool {
 	visited := make([]bool, len(rooms))
+	tree := make(map[roomPair]bool, len(rooms))
 
-	start := rand.Intn(len(rooms))
-	end := rand.Intn(len(rooms) - 1)
+	start := rng.Intn(len(rooms))
+	end := rng.Intn(len(rooms) - 1)
 	if end >= start {
 		end++
 	}
 
-	dg.Passages = connectRoomsDFS(start, rooms, visited)
+	dg.Passages = connectRoomsDFS(rng, start, rooms, visited, tree)
 	rooms[start].Type = RoomStart
 	rooms[start].Visited = true
 	rooms[end].Type = RoomEnd
-	dg.Exit = generateExitPoint(&rooms[end])
+	dg.Exit = generateExitPoint(rng, &rooms[end])
+	return tree
+}
+
+// addExtraConnections connects the share ratio of neighbouring rooms which aren't connected by the spanning tree,
+// so the level gets loops. Passages which would overlap other passages or cross rooms are skipped.
+func addExtraConnections(rng *common.RNG, dg *Dungeon, tree map[roomPair]bool, ratio float64) {
+	var candidates []roomPair
+	for i := range dg.Rooms {
+		for _, n := range getNeighbors(i) {
+			pair := newRoomPair(i, n)
+			if i < n && !tree[pair] {
+				candidates = append(candidates, pair)
+			}
+		}
+	}
+	count := int(ratio*float64(len(candidates)) + 0.5)
+	for _, i := range rng.Perm(len(candidates)) {
+		if count == 0 {
+			return
+		}
+		pair := candidates[i]
+		src, dst := &dg.Rooms[pair[0]], &dg.Rooms[pair[1]]
+		srcDoors, dstDoors := len(src.Doors), len(dst.Doors)
+		passage := generatePassage(rng, dg.Rooms[:], pair[0], pair[1])
+		if !dg.passageFits(passage, nil) {
+			src.Doors, dst.Doors = src.Doors[:srcDoors], dst.Doors[:dstDoors]
+			continue
+		}
+		dg.Passages = append(dg.Passages, passage)
+		count--
+	}
+}
+
+// Length of the straight parts of dead-end corridor stubs
+const (
+	minDeadEndLength = 2
+	maxDeadEndLength = 6
+	deadEndAttempts  = 20 // attempts to place every stub
+)
+
+// addDeadEnds adds count corridor stubs leading from a new door of a random room to nowhere.
+// A stub may turn once. It doesn't touch other passages, rooms or the map border,
+// so it stays a dead end. Stubs which can't be placed in deadEndAttempts are skipped.
+func addDeadEnds(rng *common.RNG, dg *Dungeon, count int) {
+	for i := 0; i < count; i++ {
+		for attempt := 0; attempt < deadEndAttempts; attempt++ {
+			room := &dg.Rooms[rng.Intn(len(dg.Rooms))]
+			door, dir := randomWallDoor(rng, room)
+			passage := generateDeadEnd(rng, door, dir)
+			if dg.passageFits(passage, &door) {
+				room.Doors = append(room.Doors, Door{Coords: door})
+				dg.Passages = append(dg.Passages, passage)
+				break
+			}
+		}
+	}
+}
+
+// randomWallDoor returns a random non-corner point of a random room wall and the direction out of the room.
+func randomWallDoor(rng *common.RNG, room *Room) (common.Coords, common.Coords) {
+	x := common.RandomInRange(rng, room.X+1, room.X+room.Width-2)
+	y := common.RandomInRange(rng, room.Y+1, room.Y+room.Height-2)
+	switch rng.Intn(4) {
+	case 0:
+		return common.Coords{X: x, Y: room.Y}, common.Coords{Y: -1}
+	case 1:
+		return common.Coords{X: x, Y: room.Y + room.Height - 1}, common.Coords{Y: 1}
+	case 2:
+		return common.Coords{X: room.X, Y: y}, common.Coords{X: -1}
+	default:
+		return common.Coords{X: room.X + room.Width - 1, Y: y}, common.Coords{X: 1}
+	}
+}
+
+// generateDeadEnd creates a stub going from the door in the direction dir and then maybe turning aside.
+func generateDeadEnd(rng *common.RNG, door, dir common.Coords) Passage {
+	var passage Passage
+	length := common.RandomInRange(rng, minDeadEndLength, maxDeadEndLength)
+	begin := common.Coords{X: door.X + dir.X, Y: door.Y + dir.Y}
+	turn := common.Coords{X: door.X + dir.X*length, Y: door.Y + dir.Y*length}
+	addCorridor(&passage, begin.X, begin.Y, turn.X, turn.Y)
+
+	if common.RandomBool(rng) {
+		side := common.Coords{X: dir.Y, Y: dir.X}
+		if common.RandomBool(rng) {
+			side = common.Coords{X: -side.X, Y: -side.Y}
+		}
+		length = common.RandomInRange(rng, minDeadEndLength, maxDeadEndLength)
+		addCorridor(&passage, turn.X, turn.Y, turn.X+side.X*length, turn.Y+side.Y*length)
+	}
+	return passage
+}
+
+// passageFits checks that the new passage stays inside the map and doesn't overlap other passages
+// or cross rooms with their walls.
+// If door is not nil, the passage is a dead end from this door: it also must not touch any walkable tile
+// except its own tiles and the door, nor go along the map border.
+func (d *Dungeon) passageFits(passage Passage, door *common.Coords) bool {
+	for _, tile := range passage.tiles() {
+		if tile.X < 1 || tile.Y < 1 || tile.X > common.MapWidth-2 || tile.Y > common.MapHeight-2 {
+			return false
+		}
+		if _, ok := d.TileFromRooms(tile); ok {
+			return false
+		}
+		if _, ok := d.TileFromPassages(tile); ok {
+			return false
+		}
+		if door == nil {
+			continue
+		}
+		for _, n := range []common.Coords{{X: tile.X + 1, Y: tile.Y}, {X: tile.X - 1, Y: tile.Y}, {X: tile.X, Y: tile.Y + 1}, {X: tile.X, Y: tile.Y - 1}} {
+			if n == *door || passage.Contains(n) {
+				continue
+			}
+			if t, ok := d.TileFromRooms(n); ok && t != common.WallTile {
+				return false
+			}
+			if _, ok := d.TileFromPassages(n); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tiles returns all tiles of the passage.
+func (p *Passage) tiles() []common.Coords {
+	var tiles []common.Coords
+	for _, c := range p.Path {
+		for y := min(c.Begin.Y, c.End.Y); y <= max(c.Begin.Y, c.End.Y); y++ {
+			for x := min(c.Begin.X, c.End.X); x <= max(c.Begin.X, c.End.X); x++ {
+				tiles = append(tiles, common.Coords{X: x, Y: y})
+			}
+		}
+	}
+	return tiles
 }
 
 // generatePassage creates a passage between two rooms in a dungeon grid.
-func generatePassage(rooms []Room, src int, dest int) Passage {
+func generatePassage(rng *common.RNG, rooms []Room, src int, dest int) Passage {
 	if dest < src {
 		src, dest = dest, src
 	}
@@ -105,11 +258,11 @@ func generatePassage(rooms []Room, src int, dest int) Passage {
 	rowDest, colDest := dest/cols, dest%cols
 
 	if rowSrc == rowDest {
-		generateHorizontalCorridor(&rooms[src], &rooms[dest], &passage)
+		generateHorizontalCorridor(rng, &rooms[src], &rooms[dest], &passage)
 	}
 
 	if colSrc == colDest {
-		generateVerticalCorridor(&rooms[src], &rooms[dest], &passage)
+		generateVerticalCorridor(rng, &rooms[src], &rooms[dest], &passage)
 	}
 
 	return passage
@@ -118,22 +271,22 @@ func generatePassage(rooms []Room, src int, dest int) Passage {
 // generateHorizontalCorridor creates a horizontal corridor between two rooms in a dungeon.
 // It randomly selects door positions on the source and destination rooms' walls,
 // and generates a path with optional turns to connect the rooms horizontally.
-func generateHorizontalCorridor(srcRoom *Room, destRoom *Room, pass *Passage) {
+func generateHorizontalCorridor(rng *common.RNG, srcRoom *Room, destRoom *Room, pass *Passage) {
 	srcWall, dstWall := srcRoom.X+srcRoom.Width-1, destRoom.X
 
 	srcMaxBorder, srcMinBorder := srcRoom.Y+srcRoom.Height-2, srcRoom.Y+1
-	srcY := rand.Intn(srcMaxBorder-srcMinBorder) + srcMinBorder
+	srcY := rng.Intn(srcMaxBorder-srcMinBorder) + srcMinBorder
 
 	dstMaxBorder, dstMinBorder := destRoom.Y+destRoom.Height-2, destRoom.Y+1
-	dstY := rand.Intn(dstMaxBorder-dstMinBorder) + dstMinBorder
+	dstY := rng.Intn(dstMaxBorder-dstMinBorder) + dstMinBorder
 
-	srcRoom.Doors = append(srcRoom.Doors, Door{common.Coords{X: srcWall, Y: srcY}})
-	destRoom.Doors = append(destRoom.Doors, Door{common.Coords{X: dstWall, Y: dstY}})
+	srcRoom.Doors = append(srcRoom.Doors, Door{Coords: common.Coords{X: srcWall, Y: srcY}})
+	destRoom.Doors = append(destRoom.Doors, Door{Coords: common.Coords{X: dstWall, Y: dstY}})
 
 	if srcY == dstY {
 		addCorridor(pass, srcWall+1, srcY, dstWall-1, dstY)
 	} else {
-		turnX := getRandomTurnCoord(srcWall, dstWall)
+		turnX := getRandomTurnCoord(rng, srcWall, dstWall)
 
 		addCorridor(pass, srcWall+1, srcY, turnX, srcY)
 		addCorridor(pass, turnX, srcY, turnX, dstY)
@@ -144,22 +297,22 @@ func generateHorizontalCorridor(srcRoom *Room, destRoom *Room, pass *Passage) {
 // generateVerticalCorridor creates a vertical corridor between two rooms in a dungeon.
 // It randomly selects door positions on the source and destination rooms' walls,
 // and generates a path with optional turns to connect the rooms vertically.
-func generateVerticalCorridor(srcRoom *Room, destRoom *Room, pass *Passage) {
+func generateVerticalCorridor(rng *common.RNG, srcRoom *Room, destRoom *Room, pass *Passage) {
 	srcWall, dstWall := srcRoom.Y+srcRoom.Height-1, destRoom.Y
 
 	srcMaxBorder, srcMinBorder := srcRoom.X+srcRoom.Width-2, srcRoom.X+1
-	srcX := rand.Intn(srcMaxBorder-srcMinBorder) + srcMinBorder
+	srcX := rng.Intn(srcMaxBorder-srcMinBorder) + srcMinBorder
 
 	dstMaxBorder, dstMinBorder := destRoom.X+destRoom.Width-2, destRoom.X+1
-	dstX := rand.Intn(dstMaxBorder-dstMinBorder) + dstMinBorder
+	dstX := rng.Intn(dstMaxBorder-dstMinBorder) + dstMinBorder
 
-	srcRoom.Doors = append(srcRoom.Doors, Door{common.Coords{X: srcX, Y: srcWall}})
-	destRoom.Doors = append(destRoom.Doors, Door{common.Coords{X: dstX, Y: dstWall}})
+	srcRoom.Doors = append(srcRoom.Doors, Door{Coords: common.Coords{X: srcX, Y: srcWall}})
+	destRoom.Doors = append(destRoom.Doors, Door{Coords: common.Coords{X: dstX, Y: dstWall}})
 
 	if srcX == dstX {
 		addCorridor(pass, srcX, srcWall+1, dstX, dstWall-1)
 	} else {
-		turnY := getRandomTurnCoord(srcWall, dstWall)
+		turnY := getRandomTurnCoord(rng, srcWall, dstWall)
 
 		addCorridor(pass, srcX, srcWall+1, srcX, turnY)
 		addCorridor(pass, srcX, turnY, dstX, turnY)
@@ -169,8 +322,8 @@ func generateVerticalCorridor(srcRoom *Room, destRoom *Room, pass *Passage) {
 
 // getRandomTurnCoord calculates a random intermediate coordinate between two wall positions,
 // ensuring the turn point is not too close to the source or destination walls.
-func getRandomTurnCoord(srcWall, dstWall int) int {
-	randCoord := rand.Intn(dstWall-srcWall-common.MinRoomDistance) + srcWall + 1
+func getRandomTurnCoord(rng *common.RNG, srcWall, dstWall int) int {
+	randCoord := rng.Intn(dstWall-srcWall-common.MinRoomDistance) + srcWall + 1
 	if randCoord == srcWall+1 {
 		randCoord++
 	}
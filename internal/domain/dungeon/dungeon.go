@@ -2,7 +2,6 @@ package dungeon
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
@@ -10,19 +9,29 @@ import (
 
 // Dungeon represents a collection of rooms and corridors, connected together, and exit from level coords
 type Dungeon struct {
-	Rooms       [common.MaxRoomCount]Room
-	Passages    []Passage
-	Exit        common.Coords
-	Player      Coordinator
-	LevelNumber int
-	Items       []item.Item
-	Enemies     []Coordinator
-	EventData   []string
+	Rooms         [common.MaxRoomCount]Room
+	Passages      []Passage
+	Exit          common.Coords
+	Player        Coordinator
+	LevelNumber   int
+	Items         []item.Item
+	Enemies       []Coordinator
+	EventData     []string
+	Traps         []Trap
+	SearchChance  int            // chance in percent to find an adjacent secret door or corridor by one search
+	ExitSealed    bool           // the exit doesn't work until the boss of the level is defeated
+	BossDefeated  bool           // the boss of the level has been killed
+	StairsUp      *common.Coords // stairs up to the previous level, nil on the first level
+	Artifact      *common.Coords // the artifact of the campaign, nil if it isn't on the level or has been taken
+	ArtifactTaken bool           // the player has picked up the artifact of the level
+	HeldKeys      []item.Key     // keys of the level carried away by the player while the level is stored in Floors
+	RNG           *common.RNG    // random generator of the game session, shared by all levels
 }
 
 // Passage represents a path connecting rooms in a dungeon
 type Passage struct {
 	Path []Corridor
+	Cave bool // passage is an open cave, its tiles are revealed around the player
 }
 
 // Contains checks if the given coordinates are within this passage boundaries.
@@ -36,22 +45,18 @@ func (p *Passage) Contains(c common.Coords) bool {
 }
 
 // NewDungeon create new dungeon
-func NewDungeon(player Coordinator) *Dungeon {
-	dungeon := GenerateDungeon()
+func NewDungeon(rng *common.RNG, player Coordinator) *Dungeon {
+	dungeon := GenerateDungeon(rng)
 	dungeon.Player = player
 
 	return &dungeon
 }
 
-// GenerateDungeon creates a new dungeon by generating a fixed number of rooms and their connections.
-func GenerateDungeon() Dungeon {
-	var dungeon Dungeon
-	for i := 0; i < common.MaxRoomCount; i++ {
-		dungeon.Rooms[i] = generateRoom(i)
-	}
-	generateRoomConnections(dungeon.Rooms[:], &dungeon)
-
-	return dungeon
+// GenerateDungeon creates a new dungeon by generating a fixed number of rooms and their connections
+// with the default grid generator.
+// The random generator is kept in the dungeon for the game logic of the level.
+func GenerateDungeon(rng *common.RNG) Dungeon {
+	return GridGenerator{}.Generate(rng, LayoutOptions{})
 }
 
 // TileFromEntities - get tile type under the entities
@@ -79,7 +84,8 @@ func (d *Dungeon) TileFromItems(c common.Coords) (common.TileType, bool) {
 	return common.UnknownTile, false
 }
 
-// TileFromRooms - get tile type in the room
+// TileFromRooms - get tile type in the room.
+// Secret doors look like walls until they are found.
 func (d *Dungeon) TileFromRooms(c common.Coords) (common.TileType, bool) {
 	for _, room := range d.Rooms {
 		if IsCoordInRoom(c, room) {
@@ -87,7 +93,10 @@ func (d *Dungeon) TileFromRooms(c common.Coords) (common.TileType, bool) {
 		}
 		if IsCoordInWall(c, room) {
 			for _, door := range room.Doors {
-				if c == door.Coords {
+				if c == door.Coords && !door.Hidden {
+					if door.Lock != 0 {
+						return common.LockedDoorTile, true
+					}
 					return common.DoorTile, true
 				}
 			}
@@ -97,11 +106,12 @@ func (d *Dungeon) TileFromRooms(c common.Coords) (common.TileType, bool) {
 	return common.UnknownTile, false
 }
 
-// TileFromPassages - get tile type in the passage
+// TileFromPassages - get tile type in the passage.
+// Secret corridors aren't a part of the map until they are found.
 func (d *Dungeon) TileFromPassages(c common.Coords) (common.TileType, bool) {
 	for _, passage := range d.Passages {
 		for _, corridor := range passage.Path {
-			if IsCoordInCorridor(c, corridor) {
+			if !corridor.Hidden && IsCoordInCorridor(c, corridor) {
 				return common.CorridorTile, true
 			}
 		}
@@ -115,12 +125,21 @@ func (d *Dungeon) Tile(c common.Coords) (common.TileType, error) {
 	if c == d.Exit {
 		return common.FinishTile, nil
 	}
+	if d.StairsUp != nil && c == *d.StairsUp {
+		return common.StairsUpTile, nil
+	}
+	if d.Artifact != nil && c == *d.Artifact {
+		return common.ArtifactTile, nil
+	}
 	if c == d.Player.GetCoords() {
 		return common.PlayerTile, nil
 	}
 	if tile, ok := d.TileFromEntities(c); ok {
 		return tile, nil
 	}
+	if tile, ok := d.TileFromTraps(c); ok {
+		return tile, nil
+	}
 	if tile, ok := d.TileFromRooms(c); ok {
 		return tile, nil
 	}
@@ -136,12 +155,21 @@ func (d *Dungeon) TileUnderEnemy(c common.Coords) (common.TileType, error) {
 	if c == d.Exit {
 		return common.FinishTile, nil
 	}
+	if d.StairsUp != nil && c == *d.StairsUp {
+		return common.StairsUpTile, nil
+	}
+	if d.Artifact != nil && c == *d.Artifact {
+		return common.ArtifactTile, nil
+	}
 	if c == d.Player.GetCoords() {
 		return common.PlayerTile, nil
 	}
 	if tile, ok := d.TileFromItems(c); ok {
 		return tile, nil
 	}
+	if tile, ok := d.TileFromTraps(c); ok {
+		return tile, nil
+	}
 	if tile, ok := d.TileFromRooms(c); ok {
 		return tile, nil
 	}
@@ -193,35 +221,49 @@ func (d *Dungeon) CurrentRoomWithWalls() *Room {
 	return nil
 }
 
-// FindDropPosition - get the nearest empty point to frop somethng on it
+// FindDropPosition - get the nearest empty point around the player to drop something on it
 func (d *Dungeon) FindDropPosition() *common.Coords {
-	center := d.PlayerCoords()
-	var bestPos *common.Coords
-	var minDist float64 = math.MaxFloat64
-	for dx := -2; dx <= 2; dx++ {
-		for dy := -2; dy <= 2; dy++ {
-			checkPos := common.Coords{X: center.X + dx, Y: center.Y + dy}
-			tile, _ := d.Tile(checkPos)
-			if tile == common.FloorTile {
-				dist := math.Abs(float64(dx)) + math.Abs(float64(dy))
-				if dist < minDist {
-					minDist = dist
-					bestPos = &checkPos
-				}
+	return d.FindDropPositionAt(d.PlayerCoords())
+}
+
+// FindDropPositionAt returns the tile to drop something at c: c itself if it's an empty floor or corridor tile,
+// otherwise the nearest empty one reachable from c without passing locked or secret doors. Returns nil if there's none.
+func (d *Dungeon) FindDropPositionAt(c common.Coords) *common.Coords {
+	seen := map[common.Coords]bool{c: true}
+	queue := []common.Coords{c}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		tile, err := d.Tile(cur)
+		if err != nil || tile == common.WallTile || tile == common.LockedDoorTile {
+			continue
+		}
+		if tile == common.FloorTile || tile == common.CorridorTile {
+			return &cur
+		}
+		for _, n := range neighbours(cur) {
+			if !seen[n] && d.isOpenTile(n) {
+				seen[n] = true
+				queue = append(queue, n)
 			}
 		}
 	}
-	return bestPos
+	return nil
 }
 
-// AddItemToNearestPosition - place Item on the nearest empty tile
-func (d *Dungeon) AddItemToNearestPosition(weapon item.Weapon) bool {
-	dropPos := d.FindDropPosition()
+// AddItemToNearestPosition - place Item on the nearest empty tile around the player
+func (d *Dungeon) AddItemToNearestPosition(itm item.Item) bool {
+	return d.DropItemAt(itm, d.PlayerCoords())
+}
+
+// DropItemAt places the item at c or on the nearest empty tile around it. Returns false if there's no room for it.
+func (d *Dungeon) DropItemAt(itm item.Item, c common.Coords) bool {
+	dropPos := d.FindDropPositionAt(c)
 	if dropPos == nil {
 		return false
 	}
-	weapon.Coords = *dropPos
-	d.Items = append(d.Items, &weapon)
+	itm.SetCoords(*dropPos)
+	d.Items = append(d.Items, itm)
 	return true
 }
 
@@ -247,9 +289,9 @@ func (d *Dungeon) UpdateVisibleRoomsStatus() {
 	}
 }
 
-// IsExit - check is Character on the Exit tile
+// IsExit - check is Character on the Exit tile and the exit isn't sealed
 func (d *Dungeon) IsExit() bool {
-	return d.PlayerCoords() == d.Exit
+	return d.PlayerCoords() == d.Exit && !d.ExitSealed
 }
 
 // CurrentPassage - return current passage where's the Character is on
@@ -282,13 +324,28 @@ func (d *Dungeon) UpdateVisibleArea() {
 		passage := &d.Passages[i]
 		for j := range passage.Path {
 			corridor := &passage.Path[j]
-			if IsCoordInCorridor(player, *corridor) {
+			if corridor.Hidden {
+				continue
+			}
+			if IsCoordInCorridor(player, *corridor) || (passage.Cave && isCorridorAround(player, *corridor)) {
 				corridor.Visited = true
 			}
 		}
 	}
 }
 
+// isCorridorAround checks if the corridor has a tile next to the coordinates, including diagonals.
+func isCorridorAround(c common.Coords, corridor Corridor) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if IsCoordInCorridor(common.Coords{X: c.X + dx, Y: c.Y + dy}, corridor) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Update - combine two methods to correct updating the dungeon parameters
 func (d *Dungeon) Update() {
 	d.UpdateVisibleArea()
@@ -0,0 +1,90 @@
+package dungeon
+
+import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Floors is the per-run store of the visited levels by their numbers.
+// A level the player has left is kept with its items, surviving enemies and explored fog,
+// so going up or down restores it exactly as it was left.
+type Floors map[int]Dungeon
+
+// Store keeps the level the player leaves. Events of the last turn aren't kept.
+func (f Floors) Store(d Dungeon) {
+	d.ClearEventData()
+	f[d.LevelNumber] = d
+}
+
+// Take removes the stored level from the store and returns it, false if the level hasn't been visited.
+func (f Floors) Take(level int) (Dungeon, bool) {
+	d, ok := f[level]
+	if ok {
+		delete(f, level)
+	}
+	return d, ok
+}
+
+// OnStairsUp checks if the player stands on the stairs up.
+func (d *Dungeon) OnStairsUp() bool {
+	return d.StairsUp != nil && d.PlayerCoords() == *d.StairsUp
+}
+
+// Entrance returns the place the player comes to the level from above: the stairs up,
+// or the first floor tile of the start room on a level without stairs.
+func (d *Dungeon) Entrance() common.Coords {
+	if d.StairsUp != nil {
+		return *d.StairsUp
+	}
+	for _, room := range d.Rooms {
+		if room.Type == RoomStart {
+			return common.Coords{X: room.X + 1, Y: room.Y + 1}
+		}
+	}
+	return d.Exit
+}
+
+// ArrivalPoint returns the nearest free floor tile around c, where the player can be put without stepping
+// on the stairs, the exit, an item, an enemy or a trap. Returns c if there's no such tile.
+func (d *Dungeon) ArrivalPoint(c common.Coords) common.Coords {
+	if tile, ok := d.FreeTileNear(c); ok {
+		return tile
+	}
+	return c
+}
+
+// FreeTileNear returns the nearest room floor tile around c that holds nothing: no exit, stairs, artifact,
+// player, item, enemy or trap. The search goes through the open tiles reachable from c and never returns c itself.
+// Returns false if all of them are taken.
+func (d *Dungeon) FreeTileNear(c common.Coords) (common.Coords, bool) {
+	seen := map[common.Coords]bool{c: true}
+	queue := []common.Coords{c}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbours(cur) {
+			if seen[n] || !d.isOpenTile(n) {
+				continue
+			}
+			seen[n] = true
+			if d.onAnyFloor(n) && !d.occupied(n) {
+				return n, true
+			}
+			queue = append(queue, n)
+		}
+	}
+	return common.Coords{}, false
+}
+
+// occupied checks if the tile holds the exit, the stairs up, the artifact, the player, an item, an enemy or a trap.
+func (d *Dungeon) occupied(c common.Coords) bool {
+	if c == d.Exit || (d.StairsUp != nil && c == *d.StairsUp) || (d.Artifact != nil && c == *d.Artifact) {
+		return true
+	}
+	if d.Player != nil && c == d.Player.GetCoords() {
+		return true
+	}
+	if _, ok := d.TileFromEntities(c); ok {
+		return true
+	}
+	return d.TrapAt(c) != nil
+}
@@ -0,0 +1,52 @@
+package dungeon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+func TestFreeTileNearSkipsOccupiedTiles(t *testing.T) {
+	d := newTestLevel(t, testMap)
+	stairs := common.Coords{X: 14, Y: 1}
+	d.StairsUp = &stairs
+	d.Items = append(d.Items, &item.Key{Lock: 1, Coords: common.Coords{X: 15, Y: 2}})
+	d.Enemies = append(d.Enemies, &testUnit{common.Coords{X: 14, Y: 2}})
+
+	tile, ok := d.FreeTileNear(d.Exit)
+	if !ok {
+		t.Fatal("no free tile found")
+	}
+	if want := (common.Coords{X: 13, Y: 1}); tile != want {
+		t.Errorf("FreeTileNear(exit) = (%d, %d), want (%d, %d)", tile.X, tile.Y, want.X, want.Y)
+	}
+
+	d.Traps = append(d.Traps, Trap{Coords: tile})
+	for _, c := range []common.Coords{{X: 12, Y: 1}, {X: 12, Y: 2}, {X: 13, Y: 2}} {
+		d.Items = append(d.Items, &item.Key{Lock: 1, Coords: c})
+	}
+	for _, c := range []common.Coords{{X: 1, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 4, Y: 1}, {X: 4, Y: 2}} {
+		d.Enemies = append(d.Enemies, &testUnit{c})
+	}
+	if tile, ok := d.FreeTileNear(d.Exit); ok {
+		t.Errorf("FreeTileNear(exit) = (%d, %d) on a full level", tile.X, tile.Y)
+	}
+	if got := d.ArrivalPoint(d.Exit); got != d.Exit {
+		t.Errorf("ArrivalPoint(exit) = (%d, %d) on a full level, want the exit", got.X, got.Y)
+	}
+}
+
+func TestParseASCIIMapNeedsFreeTiles(t *testing.T) {
+	full := `------
+|@fff|
+|.fE.|
+------`
+	if _, err := ParseASCIIMap(full); !errors.Is(err, ErrInvalidMap) {
+		t.Errorf("ParseASCIIMap() = %v, want ErrInvalidMap for a map with 2 free tiles", err)
+	}
+	if _, err := ParseASCIIMap(testMap); err != nil {
+		t.Errorf("ParseASCIIMap() = %v, want nil", err)
+	}
+}
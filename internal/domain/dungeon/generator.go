@@ -0,0 +1,96 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Names of the level generators, used in the level config
+const (
+	GridGeneratorName = "grid" // 3x3 grid of rooms joined by DFS corridors
+	BSPGeneratorName  = "bsp"  // rooms in the leaves of binary space partition
+	CaveGeneratorName = "cave" // small chambers inside cellular automata caves
+)
+
+// ErrUnknownGenerator is returned when there's no level generator with the requested name.
+var ErrUnknownGenerator = errors.New("unknown level generator")
+
+// Generator builds the layout of a level: rooms, passages, start room and exit.
+// Every generator fills all common.MaxRoomCount rooms and connects them,
+// so the rest of the game doesn't depend on the generator used.
+type Generator interface {
+	Generate(rng *common.RNG, opts LayoutOptions) Dungeon
+}
+
+// LayoutOptions tunes the passages of the generated level.
+type LayoutOptions struct {
+	// ExtraConnections is the share (0..1) of neighbouring rooms, which aren't connected yet,
+	// that get an extra passage forming a loop. Neighbours are the rooms next to each other in the 3x3 grid
+	// of the grid and cave layouts and the rooms of touching leaves in the BSP layout.
+	ExtraConnections float64
+	// DeadEnds is the number of dead-end corridor stubs.
+	DeadEnds int
+}
+
+// generators contains all known level generators by name
+var generators = map[string]Generator{
+	GridGeneratorName: GridGenerator{},
+	BSPGeneratorName:  BSPGenerator{},
+	CaveGeneratorName: CaveGenerator{},
+}
+
+// GeneratorByName returns the level generator with the given name.
+// Empty name means the default grid generator.
+func GeneratorByName(name string) (Generator, error) {
+	if name == "" {
+		return GridGenerator{}, nil
+	}
+	gen, ok := generators[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGenerator, name)
+	}
+	return gen, nil
+}
+
+// GeneratorNames returns sorted names of all known level generators.
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GridGenerator places one room in every cell of the 3x3 grid and connects neighbouring rooms
+// by DFS spanning tree of corridors.
+type GridGenerator struct{}
+
+// Generate creates a new grid dungeon.
+func (GridGenerator) Generate(rng *common.RNG, opts LayoutOptions) Dungeon {
+	dungeon := Dungeon{RNG: rng}
+	for i := 0; i < common.MaxRoomCount; i++ {
+		dungeon.Rooms[i] = generateRoom(rng, i)
+	}
+	tree := generateRoomConnections(rng, dungeon.Rooms[:], &dungeon)
+	addExtraConnections(rng, &dungeon, tree, opts.ExtraConnections)
+	addDeadEnds(rng, &dungeon, opts.DeadEnds)
+
+	return dungeon
+}
+
+// setStartAndEnd randomly selects different start and end rooms and places the exit in the end room.
+func setStartAndEnd(rng *common.RNG, dg *Dungeon) {
+	start := rng.Intn(len(dg.Rooms))
+	end := rng.Intn(len(dg.Rooms) - 1)
+	if end >= start {
+		end++
+	}
+	dg.Rooms[start].Type = RoomStart
+	dg.Rooms[start].Visited = true
+	dg.Rooms[end].Type = RoomEnd
+	dg.Exit = generateExitPoint(rng, &dg.Rooms[end])
+}
@@ -0,0 +1,190 @@
+package dungeon
+
+import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+// DoorLock returns the lock number of the door at the coordinates, 0 if there's no locked door.
+func (d *Dungeon) DoorLock(c common.Coords) int {
+	for _, room := range d.Rooms {
+		for _, door := range room.Doors {
+			if door.Coords == c && door.Lock != 0 {
+				return door.Lock
+			}
+		}
+	}
+	return 0
+}
+
+// UnlockDoor opens all doors with the lock number.
+func (d *Dungeon) UnlockDoor(lock int) {
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Lock == lock {
+				d.Rooms[i].Doors[j].Lock = 0
+			}
+		}
+	}
+}
+
+// LockDoors locks up to count random doors of the rooms except the start room.
+// Locks are numbered in the order they can be opened: the key of every lock can be reached from the start room
+// through the doors with smaller numbers only, so the level can't soft-lock.
+// Returns the indices of the rooms where the keys must be placed, the key of lock n goes to the room n-1.
+func (d *Dungeon) LockDoors(rng *common.RNG, count int) []int {
+	var start common.Coords
+	var candidates []*Door
+	for i := range d.Rooms {
+		room := &d.Rooms[i]
+		if room.Type == RoomStart {
+			start = common.Coords{X: room.X + 1, Y: room.Y + 1}
+			continue
+		}
+		for j := range room.Doors {
+			candidates = append(candidates, &room.Doors[j])
+		}
+	}
+
+	locked := make(map[common.Coords]bool, count)
+	for _, i := range rng.Perm(len(candidates)) {
+		if len(locked) == count {
+			break
+		}
+		locked[candidates[i].Coords] = true
+	}
+
+	var keyRooms []int
+	for len(locked) > 0 {
+		reachable := d.reachableTiles(start, locked)
+		door, ok := nextLockedDoor(locked, reachable)
+		if !ok {
+			break
+		}
+		var rooms []int
+		for i, room := range d.Rooms {
+			if reachable[common.Coords{X: room.X + 1, Y: room.Y + 1}] {
+				rooms = append(rooms, i)
+			}
+		}
+		keyRooms = append(keyRooms, rooms[rng.Intn(len(rooms))])
+		d.lockDoorAt(door, len(keyRooms))
+		delete(locked, door)
+	}
+	return keyRooms
+}
+
+// nextLockedDoor returns the locked door next to the reachable tiles, checking the map in row order
+// to keep the generation reproducible.
+func nextLockedDoor(locked, reachable map[common.Coords]bool) (common.Coords, bool) {
+	for y := 0; y < common.MapHeight; y++ {
+		for x := 0; x < common.MapWidth; x++ {
+			c := common.Coords{X: x, Y: y}
+			if !locked[c] {
+				continue
+			}
+			for _, n := range neighbours(c) {
+				if reachable[n] {
+					return c, true
+				}
+			}
+		}
+	}
+	return common.Coords{}, false
+}
+
+// lockDoorAt sets the lock number to all doors at the coordinates.
+func (d *Dungeon) lockDoorAt(c common.Coords, lock int) {
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Coords == c {
+				d.Rooms[i].Doors[j].Lock = lock
+			}
+		}
+	}
+}
+
+// Solvable checks that the player can open every locked door with a key picked up on the way
+// and get from the start room to the exit.
+func (d *Dungeon) Solvable() bool {
+	var start common.Coords
+	locked := make(map[common.Coords]bool)
+	for _, room := range d.Rooms {
+		if room.Type == RoomStart {
+			start = common.Coords{X: room.X + 1, Y: room.Y + 1}
+		}
+		for _, door := range room.Doors {
+			if door.Lock != 0 {
+				locked[door.Coords] = true
+			}
+		}
+	}
+
+	for {
+		reachable := d.reachableTiles(start, locked)
+		opened := false
+		for _, it := range d.Items {
+			key, ok := it.(*item.Key)
+			if !ok || !reachable[key.Coords] {
+				continue
+			}
+			for c := range locked {
+				if d.DoorLock(c) == key.Lock {
+					delete(locked, c)
+					opened = true
+				}
+			}
+		}
+		if !opened {
+			return len(locked) == 0 && reachable[d.Exit]
+		}
+	}
+}
+
+// reachableTiles returns the floor, door and corridor tiles which can be reached from the start
+// without passing the blocked tiles.
+func (d *Dungeon) reachableTiles(start common.Coords, blocked map[common.Coords]bool) map[common.Coords]bool {
+	reachable := map[common.Coords]bool{start: true}
+	queue := []common.Coords{start}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbours(c) {
+			if reachable[n] || blocked[n] || !d.isOpenTile(n) {
+				continue
+			}
+			reachable[n] = true
+			queue = append(queue, n)
+		}
+	}
+	return reachable
+}
+
+// isOpenTile checks if the tile is a floor, a door or a corridor, regardless of the door locks.
+// Secret doors and corridors are open too, they can always be found by searching.
+func (d *Dungeon) isOpenTile(c common.Coords) bool {
+	for _, room := range d.Rooms {
+		if IsCoordInRoom(c, room) {
+			return true
+		}
+		if IsCoordInWall(c, room) {
+			for _, door := range room.Doors {
+				if door.Coords == c {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	for i := range d.Passages {
+		if d.Passages[i].Contains(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// neighbours returns 4 tiles next to the coordinates.
+func neighbours(c common.Coords) []common.Coords {
+	return []common.Coords{{X: c.X + 1, Y: c.Y}, {X: c.X - 1, Y: c.Y}, {X: c.X, Y: c.Y + 1}, {X: c.X, Y: c.Y - 1}}
+}
@@ -0,0 +1,93 @@
+package dungeon
+
+import (
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+// testMap is two rooms joined by a corridor, with a door at (5, 1) of the start room
+// and a door at (11, 1) of the end room.
+const testMap = `------     ------
+|@...+#####+...E|
+|....|     |....|
+------     ------`
+
+// testUnit stands in for the player and the enemies, which live in a package importing this one.
+type testUnit struct {
+	common.Coords
+}
+
+func (u *testUnit) GetCoords() common.Coords  { return u.Coords }
+func (u *testUnit) SetCoords(c common.Coords) { u.Coords = c }
+
+// newTestLevel builds the level of the ASCII map with the player at the start.
+func newTestLevel(t *testing.T, text string) Dungeon {
+	t.Helper()
+	m, err := ParseASCIIMap(text)
+	if err != nil {
+		t.Fatalf("ParseASCIIMap: %v", err)
+	}
+	d := m.NewLayout()
+	d.Player = &testUnit{Coords: m.Start}
+	return d
+}
+
+// lockDoor puts the lock on the door at the coordinates.
+func lockDoor(t *testing.T, d *Dungeon, c common.Coords, lock int) {
+	t.Helper()
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Coords == c {
+				d.Rooms[i].Doors[j].Lock = lock
+				return
+			}
+		}
+	}
+	t.Fatalf("no door at (%d, %d)", c.X, c.Y)
+}
+
+func TestSolvable(t *testing.T) {
+	startDoor, endDoor := common.Coords{X: 5, Y: 1}, common.Coords{X: 11, Y: 1}
+	key := func(lock, x, y int) *item.Key {
+		return &item.Key{Lock: lock, Coords: common.Coords{X: x, Y: y}}
+	}
+	tests := []struct {
+		name  string
+		locks map[common.Coords]int
+		keys  []*item.Key
+		want  bool
+	}{
+		{name: "open doors", want: true},
+		{name: "locked door without a key", locks: map[common.Coords]int{startDoor: 1}},
+		{name: "key in the start room", locks: map[common.Coords]int{startDoor: 1}, keys: []*item.Key{key(1, 2, 2)}, want: true},
+		{name: "key behind its own door", locks: map[common.Coords]int{startDoor: 1}, keys: []*item.Key{key(1, 13, 2)}},
+		{name: "key of another lock", locks: map[common.Coords]int{startDoor: 1}, keys: []*item.Key{key(2, 2, 2)}},
+		{
+			name:  "keys opening the doors in turn",
+			locks: map[common.Coords]int{startDoor: 1, endDoor: 2},
+			keys:  []*item.Key{key(1, 2, 2), key(2, 8, 1)},
+			want:  true,
+		},
+		{
+			name:  "second key behind the second door",
+			locks: map[common.Coords]int{startDoor: 1, endDoor: 2},
+			keys:  []*item.Key{key(1, 2, 2), key(2, 13, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestLevel(t, testMap)
+			for c, lock := range tt.locks {
+				lockDoor(t, &d, c, lock)
+			}
+			for _, k := range tt.keys {
+				d.Items = append(d.Items, k)
+			}
+			if got := d.Solvable(); got != tt.want {
+				t.Errorf("Solvable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
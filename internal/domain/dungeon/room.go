@@ -1,8 +1,6 @@
 package dungeon
 
 import (
-	"math/rand"
-
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 )
 
@@ -16,6 +14,14 @@ const (
 	RoomStart
 	// RoomEnd is room with exit to the next level
 	RoomEnd
+	// RoomVault is treasure vault with extra loot
+	RoomVault
+	// RoomLair is monster lair with extra enemies carrying more treasure
+	RoomLair
+	// RoomShrine is room with a one-time blessing for the player
+	RoomShrine
+	// RoomArena is the end room of the last level, where the boss guards the exit
+	RoomArena
 )
 
 // Visibility represent status of area to check if it needed to be filled by a fog of war
@@ -40,24 +46,37 @@ type Room struct {
 	Type          RoomType   // Type of room in dungeon
 	Visited       bool       // Indicates whether the room has been visited by a player
 	Visible       Visibility // Indicates is room visible by a player
+	Blessing      *Blessing  // Blessing of the shrine, nil if it's already received or the room isn't a shrine
+}
+
+// Blessing is a permanent bonus given by a shrine. The player is also fully healed.
+type Blessing struct {
+	MaxHealth int
+	Strength  int
+	Agility   int
 }
 
 // generateRoom creates a randomly sized and positioned room within a grid layout.
 // Returns a ready Room.
-func generateRoom(number int) Room {
+func generateRoom(rng *common.RNG, number int) Room {
+	return generateSizedRoom(rng, number, common.MaxRoomWidth, common.MaxRoomHeight)
+}
+
+// generateSizedRoom creates a random room not bigger than maxWidth x maxHeight within a grid layout cell.
+func generateSizedRoom(rng *common.RNG, number int, maxWidth, maxHeight int) Room {
 	var room Room
 
 	col := number % common.RoomsInRow
 	row := number / common.RoomsInColumn
 
-	room.Height = rand.Intn(common.MaxRoomHeight-common.MinRoomHeight+1) + common.MinRoomHeight
-	room.Width = rand.Intn(common.MaxRoomWidth-common.MinRoomWidth+1) + common.MinRoomWidth
+	room.Height = rng.Intn(maxHeight-common.MinRoomHeight+1) + common.MinRoomHeight
+	room.Width = rng.Intn(maxWidth-common.MinRoomWidth+1) + common.MinRoomWidth
 
 	cellX := col * (common.MaxRoomWidth + common.MinRoomDistance)
 	cellY := row * (common.MaxRoomHeight + common.MinRoomDistance)
 
-	room.X = cellX + rand.Intn(common.MaxRoomWidth-room.Width+1)
-	room.Y = cellY + rand.Intn(common.MaxRoomHeight-room.Height+1)
+	room.X = cellX + rng.Intn(common.MaxRoomWidth-room.Width+1)
+	room.Y = cellY + rng.Intn(common.MaxRoomHeight-room.Height+1)
 	room.Visited = false
 
 	return room
@@ -85,13 +104,23 @@ func (r *Room) ContainsIncludeWalls(c common.Coords) bool {
 		c.Y >= r.Y && c.Y <= r.Y+r.Height-1
 }
 
-// generateExitPoint creates point for exit from level in specified room
-func generateExitPoint(room *Room) common.Coords {
-	minX, minY := room.X+1, room.Y+1
-	maxX, maxY := room.X+room.Width-2, room.Y+room.Height-2
-
-	randXCoord := rand.Intn(maxX-minX-1) + minX + 1
-	randYCoord := rand.Intn(maxY-minY-1) + minY + 1
+// generateExitPoint creates point for exit from level in specified room.
+// The exit keeps a tile away from the walls where the floor is wide enough for it.
+func generateExitPoint(rng *common.RNG, room *Room) common.Coords {
+	return common.Coords{
+		X: exitAxisCoord(rng, room.X+1, room.X+room.Width-2),
+		Y: exitAxisCoord(rng, room.Y+1, room.Y+room.Height-2),
+	}
+}
 
-	return common.Coords{X: randXCoord, Y: randYCoord}
+// exitAxisCoord picks a random coordinate between the floor bounds minC and maxC,
+// leaving out the tiles next to the walls unless the floor is narrower than three tiles.
+func exitAxisCoord(rng *common.RNG, minC, maxC int) int {
+	if maxC-minC >= 2 {
+		minC, maxC = minC+1, maxC-1
+	}
+	if maxC <= minC {
+		return minC
+	}
+	return rng.Intn(maxC-minC+1) + minC
 }
@@ -0,0 +1,100 @@
+package dungeon
+
+import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// HideSecrets turns up to doors random doors and up to corridors random corridor segments into secret ones.
+// Doors of the start room and locked doors stay visible, caves have no secret corridors.
+// Secret parts can always be found by searching, so they don't break the solvability of the level.
+func (d *Dungeon) HideSecrets(rng *common.RNG, doors, corridors int) {
+	var doorCandidates []*Door
+	for i := range d.Rooms {
+		if d.Rooms[i].Type == RoomStart {
+			continue
+		}
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Lock == 0 {
+				doorCandidates = append(doorCandidates, &d.Rooms[i].Doors[j])
+			}
+		}
+	}
+	for i, n := range rng.Perm(len(doorCandidates)) {
+		if i == doors {
+			break
+		}
+		d.hideDoorAt(doorCandidates[n].Coords)
+	}
+
+	var corridorCandidates []*Corridor
+	for i := range d.Passages {
+		if d.Passages[i].Cave {
+			continue
+		}
+		for j := range d.Passages[i].Path {
+			corridorCandidates = append(corridorCandidates, &d.Passages[i].Path[j])
+		}
+	}
+	for i, n := range rng.Perm(len(corridorCandidates)) {
+		if i == corridors {
+			break
+		}
+		corridorCandidates[n].Hidden = true
+	}
+}
+
+// hideDoorAt makes secret all doors at the coordinates, the rooms may share a door.
+func (d *Dungeon) hideDoorAt(c common.Coords) {
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Coords == c {
+				d.Rooms[i].Doors[j].Hidden = true
+			}
+		}
+	}
+}
+
+// Search looks for secret doors and corridors on the tiles around the player, including diagonals.
+// Every secret part nearby is found with the SearchChance of the level; found corridors are shown on the map at once.
+// Returns the number of found secrets.
+func (d *Dungeon) Search() int {
+	player := d.PlayerCoords()
+	found := 0
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			door := &d.Rooms[i].Doors[j]
+			if door.Hidden && isAround(player, door.Coords) && d.RNG.Intn(100) < d.SearchChance {
+				d.revealDoorAt(door.Coords)
+				found++
+			}
+		}
+	}
+	for i := range d.Passages {
+		for j := range d.Passages[i].Path {
+			corridor := &d.Passages[i].Path[j]
+			if corridor.Hidden && isCorridorAround(player, *corridor) && d.RNG.Intn(100) < d.SearchChance {
+				corridor.Hidden = false
+				corridor.Visited = true
+				found++
+			}
+		}
+	}
+	return found
+}
+
+// revealDoorAt makes visible all doors at the coordinates.
+func (d *Dungeon) revealDoorAt(c common.Coords) {
+	for i := range d.Rooms {
+		for j := range d.Rooms[i].Doors {
+			if d.Rooms[i].Doors[j].Coords == c {
+				d.Rooms[i].Doors[j].Hidden = false
+			}
+		}
+	}
+}
+
+// isAround checks if the tile is next to the coordinates, including diagonals.
+func isAround(c, tile common.Coords) bool {
+	dx, dy := c.X-tile.X, c.Y-tile.Y
+	return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1 && (dx != 0 || dy != 0)
+}
@@ -0,0 +1,107 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// ErrUnknownRoomType is returned when there's no room type with the requested name.
+var ErrUnknownRoomType = errors.New("unknown room type")
+
+// roomTypeNames contains the room types which can be assigned by weights in the level config
+var roomTypeNames = map[string]RoomType{
+	"plain":  RoomPlain,
+	"vault":  RoomVault,
+	"lair":   RoomLair,
+	"shrine": RoomShrine,
+}
+
+// RoomTypeByName returns the room type with the given config name.
+func RoomTypeByName(name string) (RoomType, error) {
+	roomType, ok := roomTypeNames[name]
+	if !ok {
+		return RoomPlain, fmt.Errorf("%w: %q", ErrUnknownRoomType, name)
+	}
+	return roomType, nil
+}
+
+// RoomTypeNames returns sorted names of the room types which can be assigned by weights.
+func RoomTypeNames() []string {
+	names := make([]string, 0, len(roomTypeNames))
+	for name := range roomTypeNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AssignRoomTypes gives every plain room a random type chosen by the weights.
+// The start and end rooms keep their types. Nothing changes if all weights are zero.
+func (d *Dungeon) AssignRoomTypes(rng *common.RNG, weights map[RoomType]int) {
+	// map order is random, so the types are sorted to keep generation reproducible by seed
+	types := make([]RoomType, 0, len(weights))
+	total := 0
+	for roomType, weight := range weights {
+		types = append(types, roomType)
+		total += weight
+	}
+	if total <= 0 {
+		return
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for i := range d.Rooms {
+		if d.Rooms[i].Type != RoomPlain {
+			continue
+		}
+		roll := rng.Intn(total)
+		for _, roomType := range types {
+			if roll < weights[roomType] {
+				d.Rooms[i].Type = roomType
+				break
+			}
+			roll -= weights[roomType]
+		}
+	}
+}
+
+// TakeBlessing returns the blessing of the shrine the player stands in and removes it from the shrine.
+func (d *Dungeon) TakeBlessing() (Blessing, bool) {
+	player := d.PlayerCoords()
+	for i := range d.Rooms {
+		room := &d.Rooms[i]
+		if room.Type == RoomShrine && room.Blessing != nil && IsCoordInRoom(player, *room) {
+			blessing := *room.Blessing
+			room.Blessing = nil
+			return blessing, true
+		}
+	}
+	return Blessing{}, false
+}
+
+// MakeArena turns the biggest room except the start room into the boss arena and moves the exit there.
+// The exit is sealed until the boss is defeated. The former end room becomes a plain room.
+// Returns the index of the arena.
+func (d *Dungeon) MakeArena(rng *common.RNG) int {
+	arena := -1
+	for i, room := range d.Rooms {
+		if room.Type == RoomStart {
+			continue
+		}
+		if arena == -1 || room.FloorWidth()*room.FloorHeight() > d.Rooms[arena].FloorWidth()*d.Rooms[arena].FloorHeight() {
+			arena = i
+		}
+	}
+	for i := range d.Rooms {
+		if d.Rooms[i].Type == RoomEnd {
+			d.Rooms[i].Type = RoomPlain
+		}
+	}
+	d.Rooms[arena].Type = RoomArena
+	d.Exit = generateExitPoint(rng, &d.Rooms[arena])
+	d.ExitSealed = true
+	return arena
+}
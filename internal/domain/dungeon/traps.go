@@ -0,0 +1,131 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// TrapType represents the effect of a trap
+type TrapType int
+
+const (
+	// TrapTeleport moves the player to a random place of the level
+	TrapTeleport TrapType = iota
+	// TrapSleep puts the player to sleep for a turn
+	TrapSleep
+	// TrapArrow shoots an arrow dealing damage
+	TrapArrow
+	// TrapDoor drops the player to the next level
+	TrapDoor
+)
+
+// ErrUnknownTrapType is returned when there's no trap type with the requested name.
+var ErrUnknownTrapType = errors.New("unknown trap type")
+
+// trapTypeNames contains the trap types by their config names
+var trapTypeNames = map[string]TrapType{
+	"teleport": TrapTeleport,
+	"sleep":    TrapSleep,
+	"arrow":    TrapArrow,
+	"trapdoor": TrapDoor,
+}
+
+// TrapTypeByName returns the trap type with the given config name.
+func TrapTypeByName(name string) (TrapType, error) {
+	trapType, ok := trapTypeNames[name]
+	if !ok {
+		return TrapTeleport, fmt.Errorf("%w: %q", ErrUnknownTrapType, name)
+	}
+	return trapType, nil
+}
+
+// TrapTypeNames returns sorted names of all trap types.
+func TrapTypeNames() []string {
+	names := make([]string, 0, len(trapTypeNames))
+	for name := range trapTypeNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Trap is a hidden hazard on the room floor. It looks like floor until it's triggered or detected by searching.
+type Trap struct {
+	common.Coords
+	Type     TrapType
+	Damage   int  // damage of an arrow trap
+	Revealed bool // the trap has been triggered or detected and is shown on the map
+}
+
+// TileFromTraps - get tile type of the revealed trap, hidden traps look like floor
+func (d *Dungeon) TileFromTraps(c common.Coords) (common.TileType, bool) {
+	for _, trap := range d.Traps {
+		if trap.Coords == c && trap.Revealed {
+			return common.TrapTile, true
+		}
+	}
+	return common.UnknownTile, false
+}
+
+// TrapAt returns the trap at the coordinates, nil if there's no trap.
+func (d *Dungeon) TrapAt(c common.Coords) *Trap {
+	for i := range d.Traps {
+		if d.Traps[i].Coords == c {
+			return &d.Traps[i]
+		}
+	}
+	return nil
+}
+
+// OnTrapdoor checks if the player stands on a trapdoor.
+func (d *Dungeon) OnTrapdoor() bool {
+	trap := d.TrapAt(d.PlayerCoords())
+	return trap != nil && trap.Type == TrapDoor
+}
+
+// DetectTraps reveals hidden traps around the player, including diagonals,
+// each with the SearchChance of the level. Returns the number of detected traps.
+func (d *Dungeon) DetectTraps() int {
+	player := d.PlayerCoords()
+	found := 0
+	for i := range d.Traps {
+		trap := &d.Traps[i]
+		if !trap.Revealed && isAround(player, trap.Coords) && d.RNG.Intn(100) < d.SearchChance {
+			trap.Revealed = true
+			found++
+		}
+	}
+	return found
+}
+
+// TeleportDestination returns a random free floor tile which can be reached from the player's position
+// without passing locked doors, so a teleport never locks the player away from the keys.
+// Returns false if there's no such tile.
+func (d *Dungeon) TeleportDestination() (common.Coords, bool) {
+	locked := make(map[common.Coords]bool)
+	for _, room := range d.Rooms {
+		for _, door := range room.Doors {
+			if door.Lock != 0 {
+				locked[door.Coords] = true
+			}
+		}
+	}
+	reachable := d.reachableTiles(d.PlayerCoords(), locked)
+
+	var candidates []common.Coords
+	for _, c := range d.FreeCoords() {
+		if !reachable[c] || c == d.PlayerCoords() || d.TrapAt(c) != nil {
+			continue
+		}
+		if tile, err := d.Tile(c); err == nil && tile == common.FloorTile {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return common.Coords{}, false
+	}
+	return candidates[d.RNG.Intn(len(candidates))], true
+}
@@ -0,0 +1,172 @@
+package dungeon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+// ErrInvalidLevel is returned when a generated level breaks one of the level invariants.
+var ErrInvalidLevel = errors.New("invalid level")
+
+// Validate checks the invariants every playable level must hold:
+//   - rooms fit the map and don't overlap, passages don't cross rooms
+//   - doors sit on room walls, not in the corners
+//   - there's one start room with the player and one end room or arena with the exit
+//   - the player, the exit, the stairs up, the artifact, items, enemies and traps don't share tiles and stand on the room floor or corridors
+//   - every locked door can be opened and the exit can be reached from the start
+//   - every item, enemy and the artifact can be reached from the start
+//
+// Unused rooms of zero size are skipped. Returns all found problems joined, nil for a sane level.
+func (d *Dungeon) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidLevel}, args...)...))
+	}
+
+	var startRoom, endRoom *Room
+	for i := range d.Rooms {
+		room := &d.Rooms[i]
+		if room.Width == 0 && room.Height == 0 {
+			continue
+		}
+		if room.X < 0 || room.Y < 0 || room.X+room.Width > common.MapWidth || room.Y+room.Height > common.MapHeight {
+			fail("room %d at (%d, %d) of size %dx%d is out of the map", i, room.X, room.Y, room.Width, room.Height)
+		}
+		if room.Width < 3 || room.Height < 3 {
+			fail("room %d of size %dx%d has no floor", i, room.Width, room.Height)
+		}
+		for j := i + 1; j < len(d.Rooms); j++ {
+			if roomsOverlap(*room, d.Rooms[j]) {
+				fail("rooms %d and %d overlap", i, j)
+			}
+		}
+		for _, door := range room.Doors {
+			if !IsCoordInWall(door.Coords, *room) || isRoomCorner(door.Coords, *room) {
+				fail("door (%d, %d) of room %d isn't on its wall", door.X, door.Y, i)
+			}
+		}
+		switch room.Type {
+		case RoomStart:
+			if startRoom != nil {
+				fail("more than one start room")
+			}
+			startRoom = room
+		case RoomEnd, RoomArena:
+			if endRoom != nil {
+				fail("more than one end room")
+			}
+			endRoom = room
+		}
+	}
+	if startRoom == nil || endRoom == nil {
+		fail("no start or end room")
+		return errors.Join(errs...)
+	}
+
+	for i := range d.Passages {
+		for _, tile := range d.Passages[i].tiles() {
+			if tile.X < 0 || tile.Y < 0 || tile.X >= common.MapWidth || tile.Y >= common.MapHeight {
+				fail("passage %d goes out of the map at (%d, %d)", i, tile.X, tile.Y)
+				break
+			}
+			if d.inAnyRoom(tile) {
+				fail("passage %d crosses a room at (%d, %d)", i, tile.X, tile.Y)
+				break
+			}
+		}
+	}
+
+	if !IsCoordInRoom(d.Exit, *endRoom) {
+		fail("exit (%d, %d) isn't on the floor of the end room", d.Exit.X, d.Exit.Y)
+	}
+	if d.Player == nil {
+		fail("no player")
+		return errors.Join(errs...)
+	}
+	start := d.PlayerCoords()
+	if !IsCoordInRoom(start, *startRoom) {
+		fail("player (%d, %d) isn't on the floor of the start room", start.X, start.Y)
+	}
+
+	occupied := map[common.Coords]string{start: "player"}
+	place := func(name string, c common.Coords, floorOnly bool) {
+		if other, ok := occupied[c]; ok {
+			fail("%s and %s share the tile (%d, %d)", name, other, c.X, c.Y)
+		}
+		occupied[c] = name
+		if (floorOnly && !d.onAnyFloor(c)) || !d.isOpenTile(c) {
+			fail("%s stands on a closed tile (%d, %d)", name, c.X, c.Y)
+		}
+	}
+	place("exit", d.Exit, true)
+	if d.StairsUp != nil {
+		place("stairs up", *d.StairsUp, true)
+	}
+	if d.Artifact != nil {
+		place("artifact", *d.Artifact, true)
+	}
+	for i, it := range d.Items {
+		place(fmt.Sprintf("item %d (%s)", i, item.ItemsNames[it.Type()]), it.GetCoords(), true)
+	}
+	for i, enemy := range d.Enemies {
+		place(fmt.Sprintf("enemy %d", i), enemy.GetCoords(), false)
+	}
+	for i, trap := range d.Traps {
+		place(fmt.Sprintf("trap %d", i), trap.Coords, true)
+	}
+
+	if !d.Solvable() {
+		fail("the exit can't be reached from the start with the keys of the level")
+	}
+	reachable := d.reachableTiles(start, nil)
+	for i, it := range d.Items {
+		if c := it.GetCoords(); !reachable[c] {
+			fail("item %d (%s) at (%d, %d) can't be reached", i, item.ItemsNames[it.Type()], c.X, c.Y)
+		}
+	}
+	if d.Artifact != nil && !reachable[*d.Artifact] {
+		fail("artifact at (%d, %d) can't be reached", d.Artifact.X, d.Artifact.Y)
+	}
+	for i, enemy := range d.Enemies {
+		if c := enemy.GetCoords(); !reachable[c] {
+			fail("enemy %d at (%d, %d) can't be reached", i, c.X, c.Y)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// roomsOverlap checks if two rooms share any tile, including walls. Rooms of zero size overlap nothing.
+func roomsOverlap(a, b Room) bool {
+	if a.Width == 0 || a.Height == 0 || b.Width == 0 || b.Height == 0 {
+		return false
+	}
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// isRoomCorner checks if the coordinates are one of the 4 corners of the room.
+func isRoomCorner(c common.Coords, r Room) bool {
+	return (c.X == r.X || c.X == r.X+r.Width-1) && (c.Y == r.Y || c.Y == r.Y+r.Height-1)
+}
+
+// inAnyRoom checks if the coordinates are on the floor or the walls of any room.
+func (d *Dungeon) inAnyRoom(c common.Coords) bool {
+	for i := range d.Rooms {
+		if d.Rooms[i].Width > 0 && d.Rooms[i].ContainsIncludeWalls(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// onAnyFloor checks if the coordinates are on the floor of any room.
+func (d *Dungeon) onAnyFloor(c common.Coords) bool {
+	for _, room := range d.Rooms {
+		if IsCoordInRoom(c, room) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package dungeon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
+)
+
+func TestValidate(t *testing.T) {
+	at := func(x, y int) common.Coords { return common.Coords{X: x, Y: y} }
+	key := func(x, y int) *item.Key { return &item.Key{Lock: 1, Coords: at(x, y)} }
+	tests := []struct {
+		name    string
+		change  func(d *Dungeon)
+		invalid bool
+	}{
+		{name: "map level", change: func(d *Dungeon) {}},
+		{name: "enemy in the corridor", change: func(d *Dungeon) { d.Enemies = append(d.Enemies, &testUnit{at(8, 1)}) }},
+		{name: "no player", change: func(d *Dungeon) { d.Player = nil }, invalid: true},
+		{name: "exit in the start room", change: func(d *Dungeon) { d.Exit = at(2, 2) }, invalid: true},
+		{name: "item in a wall", change: func(d *Dungeon) { d.Items = append(d.Items, key(0, 1)) }, invalid: true},
+		{name: "item under the player", change: func(d *Dungeon) { d.Items = append(d.Items, key(1, 1)) }, invalid: true},
+		{name: "items on one tile", change: func(d *Dungeon) { d.Items = append(d.Items, key(2, 2), key(2, 2)) }, invalid: true},
+		{name: "stairs in the corridor", change: func(d *Dungeon) { stairs := at(8, 1); d.StairsUp = &stairs }, invalid: true},
+		{name: "artifact on the exit", change: func(d *Dungeon) { d.Artifact = &d.Exit }, invalid: true},
+		{name: "trap on the exit", change: func(d *Dungeon) { d.Traps = append(d.Traps, Trap{Coords: d.Exit}) }, invalid: true},
+		{name: "locked door without a key", change: func(d *Dungeon) { d.Rooms[0].Doors[0].Lock = 1 }, invalid: true},
+		{name: "door in a corner", change: func(d *Dungeon) { d.Rooms[0].Doors[0].Coords = at(0, 0) }, invalid: true},
+		{name: "rooms overlap", change: func(d *Dungeon) { d.Rooms[1].X = 4 }, invalid: true},
+		{name: "room out of the map", change: func(d *Dungeon) { d.Rooms[1].Y = common.MapHeight - 2 }, invalid: true},
+		{name: "two start rooms", change: func(d *Dungeon) { d.Rooms[1].Type = RoomStart }, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestLevel(t, testMap)
+			tt.change(&d)
+			err := d.Validate()
+			if tt.invalid && !errors.Is(err, ErrInvalidLevel) {
+				t.Errorf("Validate() = %v, want ErrInvalidLevel", err)
+			}
+			if !tt.invalid && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestGenerateExitPointInSmallRooms(t *testing.T) {
+	rng := common.NewRNG(1)
+	for width := 3; width <= 8; width++ {
+		for height := 3; height <= 8; height++ {
+			room := Room{Coords: common.Coords{X: 2, Y: 3}, Size: common.Size{Width: width, Height: height}}
+			for i := 0; i < 20; i++ {
+				if exit := generateExitPoint(rng, &room); !IsCoordInRoom(exit, room) {
+					t.Fatalf("exit (%d, %d) isn't on the floor of the %dx%d room", exit.X, exit.Y, width, height)
+				}
+			}
+		}
+	}
+}
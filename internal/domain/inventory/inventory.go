@@ -1,4 +1,4 @@
-// Package inventory manages the player's collected items, including weapons, consumables,
+// Package inventory manages the player's collected items, including weapons, armors, consumables,
 // and treasure. It enforces limits and provides methods for adding and removing items.
 package inventory
 
@@ -12,9 +12,11 @@ const MaxItems = 9
 // Inventory holds all collectible items the player possesses.
 type Inventory struct {
 	Weapons  []item.Weapon // Equippable weapons
+	Armors   []item.Armor  // Wearable armors
 	Elixirs  []item.Elixir // Temporary stat-boosting potions
 	Scrolls  []item.Scroll // Magic scrolls with one-time effects
 	Foods    []item.Food   // Food items that restore health
+	Keys     []item.Key    // Keys of locked doors
 	Treasure int           // Collected gold or currency
 }
 
@@ -30,6 +32,14 @@ func (inv *Inventory) Add(itm item.Item) bool {
 		weapon, _ := itm.(*item.Weapon)
 		inv.Weapons = append(inv.Weapons, *weapon)
 
+	case item.ArmorType:
+		if len(inv.Armors) >= MaxItems {
+			return false
+		}
+
+		armor, _ := itm.(*item.Armor)
+		inv.Armors = append(inv.Armors, *armor)
+
 	case item.ElixirType:
 		if len(inv.Weapons) >= MaxItems {
 			return false
@@ -54,6 +64,14 @@ func (inv *Inventory) Add(itm item.Item) bool {
 		food, _ := itm.(*item.Food)
 		inv.Foods = append(inv.Foods, *food)
 
+	case item.KeyType:
+		if len(inv.Keys) >= MaxItems {
+			return false
+		}
+
+		key, _ := itm.(*item.Key)
+		inv.Keys = append(inv.Keys, *key)
+
 	default:
 		break
 	}
@@ -67,17 +85,33 @@ func (inv *Inventory) Delete(itm item.Item) bool {
 	switch v := itm.(type) {
 	case *item.Weapon:
 		return deleteFromSlice(&inv.Weapons, *v)
+	case *item.Armor:
+		return deleteFromSlice(&inv.Armors, *v)
 	case *item.Elixir:
 		return deleteFromSlice(&inv.Elixirs, *v)
 	case *item.Scroll:
 		return deleteFromSlice(&inv.Scrolls, *v)
 	case *item.Food:
 		return deleteFromSlice(&inv.Foods, *v)
+	case *item.Key:
+		return deleteFromSlice(&inv.Keys, *v)
 	default:
 		return false
 	}
 }
 
+// TakeKey removes the key of the lock from the inventory.
+// Returns the key and true if the inventory had it.
+func (inv *Inventory) TakeKey(lock int) (item.Key, bool) {
+	for _, key := range inv.Keys {
+		if key.Lock == lock {
+			deleteFromSlice(&inv.Keys, key)
+			return key, true
+		}
+	}
+	return item.Key{}, false
+}
+
 // deleteFromSlice is a generic helper function to remove an item from a slice.
 // Returns true if the item was found and removed.
 func deleteFromSlice[T comparable](slice *[]T, item T) bool {
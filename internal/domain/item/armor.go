@@ -0,0 +1,31 @@
+package item
+
+import "github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+
+// Armor represents a wearable item that reduces the damage taken by the player.
+type Armor struct {
+	Name    string        // Name of the armor (e.g., "Mail of the Drowned Knight")
+	Defense int           // Damage absorbed from every hit
+	Coords  common.Coords // Position of the armor on the map
+}
+
+// Type returns the item type, used for identification and rendering.
+func (a Armor) Type() Type {
+	return ArmorType
+}
+
+// Info returns the display name of the item for UI and logs.
+func (a Armor) Info() string {
+	return a.Name
+}
+
+// GetCoords returns the current position of the item on the map.
+func (a Armor) GetCoords() common.Coords {
+	return a.Coords
+}
+
+// SetCoords updates the item's position on the map.
+func (a *Armor) SetCoords(c common.Coords) {
+	a.Coords.X = c.X
+	a.Coords.Y = c.Y
+}
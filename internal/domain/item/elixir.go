@@ -2,12 +2,13 @@ package item
 
 import "github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 
-// Elixir represents a consumable potion that temporarily boosts character attributes.
+// Elixir represents a consumable potion that temporarily boosts character attributes or changes the speed.
 type Elixir struct {
 	Name      string        // Name of the elixir (e.g., "Elixir of the Hollow Vein")
 	Agility   int           // Agility boost value
 	Strength  int           // Strength boost value
 	MaxHealth int           // Max health boost value
+	Speed     int           // Speed change, positive for haste and negative for slowness
 	Coords    common.Coords // Position on the map
 	Duration  int           // Number of turns the effect lasts
 	IsActive  bool          // Whether the effect is currently active
@@ -0,0 +1,34 @@
+package item
+
+import (
+	"strconv"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Gold represents a pile of gold dropped by a killed enemy. It goes to the treasure when picked up.
+type Gold struct {
+	Amount int           // Gold in the pile
+	Coords common.Coords // Position on the map
+}
+
+// Type returns the item type, used for identification and rendering.
+func (g Gold) Type() Type {
+	return GoldType
+}
+
+// Info returns the display name of the item for UI and logs.
+func (g Gold) Info() string {
+	return strconv.Itoa(g.Amount) + " gold"
+}
+
+// GetCoords returns the current position of the item on the map.
+func (g Gold) GetCoords() common.Coords {
+	return g.Coords
+}
+
+// SetCoords updates the item's position on the map.
+func (g *Gold) SetCoords(c common.Coords) {
+	g.Coords.X = c.X
+	g.Coords.Y = c.Y
+}
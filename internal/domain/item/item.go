@@ -12,6 +12,9 @@ const (
 	ElixirType             // Potion that grants temporary stat boosts
 	ScrollType             // Magic scroll with a one-time effect
 	WeaponType             // Equippable weapon that boosts attack power
+	KeyType                // Key which opens a locked door
+	ArmorType              // Wearable armor that reduces incoming damage
+	GoldType               // Pile of gold which goes to the treasure
 )
 
 // Item is a common interface implemented by all collectible objects in the dungeon.
@@ -29,4 +32,7 @@ var ItemsNames = map[Type]string{
 	ElixirType: "elixir",
 	ScrollType: "scroll",
 	WeaponType: "weapon",
+	KeyType:    "key",
+	ArmorType:  "armor",
+	GoldType:   "gold",
 }
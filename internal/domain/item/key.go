@@ -0,0 +1,31 @@
+package item
+
+import "github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+
+// Key represents a key which opens the locked door with the same lock number.
+type Key struct {
+	Name   string        // Name of the key (e.g., "Rusty Key")
+	Lock   int           // Number of the lock opened by the key
+	Coords common.Coords // Position on the map
+}
+
+// Type returns the item type, used for identification and rendering.
+func (k Key) Type() Type {
+	return KeyType
+}
+
+// Info returns the display name of the item for UI and logs.
+func (k Key) Info() string {
+	return k.Name
+}
+
+// GetCoords returns the current position of the item on the map.
+func (k Key) GetCoords() common.Coords {
+	return k.Coords
+}
+
+// SetCoords updates the item's position on the map.
+func (k *Key) SetCoords(c common.Coords) {
+	k.Coords.X = c.X
+	k.Coords.Y = c.Y
+}
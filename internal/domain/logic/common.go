@@ -6,27 +6,45 @@ import (
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 )
 
-// HandleAction processes a player's actions in the game: fighting, moving and collecting items.
-// Monsters actions updating after every player's movement.
-func HandleAction(dir unit.Direction, dg *dungeon.Dungeon) {
-	// player := dg.Player.(*unit.Character)
+// Rules are the configured formulas of the turn.
+type Rules struct {
+	Combat   unit.Combat   // chance to hit and damage of attacks
+	Leveling unit.Leveling // progression of the player by the experience for kills
+}
+
+// HandleAction processes a player's actions in the game: fighting, moving, collecting items, visiting shrines
+// and stepping on traps. A sleeping player skips the action, a confused one moves in a random direction.
+// After the player's action the game time runs until the player can act again. Hits and kills are resolved by the rules.
+func HandleAction(dir unit.Direction, dg *dungeon.Dungeon, rules Rules) {
 	dg.ClearEventData()
 	UpdateFights(dg)
-	pAttacked := IsPlayerAttacked(dir, dg)
+	if !SkipsTurn(dg) {
+		dir = ConfusedDirection(dir, dg)
+		if !IsPlayerAttacked(dir, dg, rules) {
+			PlayerMove(dir, dg)
+			CheckSealedExit(dg)
+			CheckConsumables(dg)
+			ReceiveBlessing(dg)
+			TriggerTrap(dg)
+		}
+	}
 	RemoveDeadMonsters(dg)
-	MonsterAttack(dg)
+	EndPlayerTurn(dg, rules.Combat)
+}
 
-	if !pAttacked /* && !player.IsDead() */ {
-		PlayerMove(dir, dg)
-		CheckConsumables(dg)
+// HandleSearch processes the player's search action: the player stays in place and looks for secrets and traps around.
+// The game time runs as after a usual move.
+func HandleSearch(dg *dungeon.Dungeon, rules Rules) {
+	dg.ClearEventData()
+	UpdateFights(dg)
+	if !SkipsTurn(dg) {
+		SearchSecrets(dg)
 	}
-
-	MonstersMove(dg)
-	UpdateItemsEffects(dg)
+	EndPlayerTurn(dg, rules.Combat)
 }
 
 // IsEnemyNearby checks if the enemy is neighbour for the player.
-// It considers horizontal, vertical, and diagonal (for snake wizard) neighbourhood.
+// It considers horizontal, vertical, and diagonal (for enemies with the diagonal reach) neighbourhood.
 // Returns true if an enemy is nearby, false otherwise.
 func IsEnemyNearby(character dungeon.Coordinator, monster dungeon.Coordinator) bool {
 	enemy := monster.GetCoords()
@@ -39,7 +57,7 @@ func IsEnemyNearby(character dungeon.Coordinator, monster dungeon.Coordinator) b
 		return true
 	}
 
-	if e, ok := monster.(*unit.Enemy); ok && e.EnemyType == unit.SnakeWizard {
+	if e, ok := monster.(*unit.Enemy); ok && e.HasTrait(unit.TraitDiagonalReach) {
 
 		leftDiagonal := (enemy.X == player.X-1 && (enemy.Y == player.Y-1 || enemy.Y == player.Y+1))
 		rightDiagonal := (enemy.X == player.X+1 && (enemy.Y == player.Y-1 || enemy.Y == player.Y+1))
@@ -68,17 +86,6 @@ func GetCoordsAfterMoving(player common.Coords, dir unit.Direction) common.Coord
 	}
 }
 
-// MonstersMove iterates through all enemies in the dungeon and calls their individual Move method,
-// allowing each enemy to update its position within the dungeon's current state.
-func MonstersMove(dg *dungeon.Dungeon) {
-	for i := range dg.Enemies {
-		enemy := dg.Enemies[i].(*unit.Enemy)
-		if !enemy.InBattle {
-			enemy.Move(*dg)
-		}
-	}
-}
-
 // UpdateItemsEffects manages the duration and effects of active elixirs and scrolls in the player's inventory.
 func UpdateItemsEffects(dg *dungeon.Dungeon) {
 	player := dg.Player.(*unit.Character)
@@ -90,6 +97,7 @@ func UpdateItemsEffects(dg *dungeon.Dungeon) {
 		}
 		if elixir.Duration <= 0 {
 			elixir.IsActive = false
+			player.Speed -= elixir.Speed
 			DeleteEffectValues(dg, elixir.Agility, elixir.Strength, elixir.MaxHealth)
 			player.Inventory.Delete(elixir)
 		}
@@ -2,23 +2,29 @@ package logic
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 )
 
 // UpdateFights manages the battle state of enemies near the player.
 func UpdateFights(dg *dungeon.Dungeon) {
 	for i := range dg.Enemies {
-		enemy := dg.Enemies[i].(*unit.Enemy)
-		if IsEnemyNearby(dg.Player, enemy) {
-			if !enemy.InBattle {
-				SwitchToBattleMode(enemy)
-			}
-		} else {
-			if enemy.InBattle {
-				enemy.InBattle = false
-			}
+		UpdateFight(dg, dg.Enemies[i].(*unit.Enemy))
+	}
+}
+
+// UpdateFight puts the enemy next to the player into the battle mode and takes the others out of it.
+func UpdateFight(dg *dungeon.Dungeon, enemy *unit.Enemy) {
+	if IsEnemyNearby(dg.Player, enemy) {
+		if !enemy.InBattle {
+			SwitchToBattleMode(enemy)
+		}
+	} else {
+		if enemy.InBattle {
+			enemy.InBattle = false
 		}
 	}
 }
@@ -27,17 +33,18 @@ func UpdateFights(dg *dungeon.Dungeon) {
 func SwitchToBattleMode(enemy *unit.Enemy) {
 	enemy.InBattle = true
 	enemy.Visibility = true
-	if enemy.EnemyType == unit.Ogr {
-		enemy.SkipNextTurn = false
+	if enemy.HasTrait(unit.TraitRests) {
+		enemy.RemoveStatus(unit.StatusSleep)
 	}
-	if enemy.EnemyType == unit.Vampire {
-		enemy.FirstHitMiss = true
+	if enemy.HasTrait(unit.TraitEvasive) {
+		enemy.AddStatus(unit.Status{Type: unit.StatusEvasion, Duration: unit.UntilUsed})
 	}
 }
 
 // IsPlayerAttacked checks if the player is attacked when moving in a specific direction.
+// The hit is resolved by the combat formula, the experience for a kill raises the player's level by the leveling rules.
 // Returns true if the player is attacked (or try to attack), false otherwise.
-func IsPlayerAttacked(dir unit.Direction, dg *dungeon.Dungeon) bool {
+func IsPlayerAttacked(dir unit.Direction, dg *dungeon.Dungeon, rules Rules) bool {
 	newPlayerCoords := GetCoordsAfterMoving(dg.Player.GetCoords(), dir)
 	player := dg.Player.(*unit.Character)
 	isAttacked := false
@@ -46,69 +53,112 @@ func IsPlayerAttacked(dir unit.Direction, dg *dungeon.Dungeon) bool {
 		enemy := dg.Enemies[i].(*unit.Enemy)
 		if newPlayerCoords == enemy.Coords {
 			isAttacked = true
-			if !player.SkipNextTurn {
-				hit, gold := player.HitEnemy(enemy)
-				UpdateAttackData(dg, player, enemy, hit, gold)
-				break
-			} else {
-				player.SkipNextTurn = false
+			hit := player.HitEnemy(enemy, dg.RNG, rules.Combat)
+			UpdateAttackData(dg, player, enemy, hit)
+			if hit.Killed && player.GainXP(hit.XP, rules.Leveling) > 0 {
+				AnnounceLevelUp(dg, player, rules.Leveling)
+			}
+			if enemy.NextPhase() {
+				AnnounceBossPhase(dg, enemy)
 			}
+			break
 		}
 	}
 
 	return isAttacked
 }
 
-// MonsterAttack handles enemy attacks on the player when enemies are nearby and in battle mode.
-func MonsterAttack(dg *dungeon.Dungeon) {
+// MonsterAttack handles the attack of the enemy on the player. The hit is resolved by the combat formula,
+// an enemy which rests after blows falls asleep for a turn.
+func MonsterAttack(dg *dungeon.Dungeon, enemy *unit.Enemy, combat unit.Combat) {
 	player := dg.Player.(*unit.Character)
-
-	for i := range dg.Enemies {
-		enemy := dg.Enemies[i].(*unit.Enemy)
-		if IsEnemyNearby(player, enemy) && enemy.InBattle {
-			skip := enemy.SkipNextTurn
-
-			if enemy.EnemyType == unit.Ogr {
-				enemy.SkipNextTurn = !enemy.SkipNextTurn
-			}
-
-			if !skip {
-				hit := enemy.HitCharacter(player)
-				UpdateAttackData(dg, enemy, player, hit, 0)
-				break
-			}
-		}
+	hit := enemy.HitCharacter(player, dg.RNG, combat)
+	UpdateAttackData(dg, enemy, player, hit)
+	if enemy.HasTrait(unit.TraitRests) {
+		enemy.AddStatus(unit.Status{Type: unit.StatusSleep, Duration: 1})
 	}
 }
 
 // UpdateAttackData add the result of an attack between a fighter and a defender in EventData array
 // for output in game screen.
-func UpdateAttackData(dg *dungeon.Dungeon, attacker, defender unit.Fighter, goodHit bool, gold int) {
+func UpdateAttackData(dg *dungeon.Dungeon, attacker, defender unit.Fighter, hit unit.Hit) {
+	prefix := ""
+	if hit.Critical {
+		prefix = "Critical hit! "
+	}
+
 	if enemy, ok := defender.(*unit.Enemy); ok {
-		if !goodHit {
-			dg.AddEventData("You missed " + unit.EnemyNames[enemy.EnemyType] + "...")
-		} else if goodHit && gold == 0 {
-			dg.AddEventData("You attacked " + unit.EnemyNames[enemy.EnemyType] + "!")
-		} else if goodHit && gold > 0 {
-			dg.AddEventData("You defeated " + unit.EnemyNames[enemy.EnemyType] + "! You got " + strconv.Itoa(gold) + " gold.")
+		name := enemy.Name()
+		if !hit.Landed {
+			dg.AddEventData("You missed " + name + "...")
+		} else if !hit.Killed {
+			dg.AddEventData(prefix + "You hit " + name + " for " + strconv.Itoa(hit.Damage) + " damage!")
+		} else {
+			dg.AddEventData(prefix + "You defeated " + name + " with " + strconv.Itoa(hit.Damage) +
+				" damage! You got " + strconv.Itoa(hit.XP) + " XP.")
 		}
 	}
 
 	if enemy, ok := attacker.(*unit.Enemy); ok {
-		if !goodHit {
-			dg.AddEventData(unit.EnemyNames[enemy.EnemyType] + " missed.")
-		} else if goodHit {
-			dg.AddEventData(unit.EnemyNames[enemy.EnemyType] + " attacked!")
+		name := enemy.Name()
+		if !hit.Landed {
+			dg.AddEventData(name + " missed.")
+		} else if hit.Damage == 0 {
+			dg.AddEventData(name + " attacked!")
+		} else {
+			dg.AddEventData(prefix + name + " hit you for " + strconv.Itoa(hit.Damage) + " damage!")
 		}
 	}
 }
 
-// RemoveDeadMonsters removes dead enemies from the dungeon's enemy list.
+// AnnounceLevelUp adds the message of the level the player has just reached.
+func AnnounceLevelUp(dg *dungeon.Dungeon, player *unit.Character, l unit.Leveling) {
+	dg.AddEventData("You reached level " + strconv.Itoa(player.Level) + "! Max health +" + strconv.Itoa(l.MaxHealth) +
+		", strength +" + strconv.Itoa(l.Strength) + ", agility +" + strconv.Itoa(l.Agility) + ".")
+}
+
+// AnnounceBossPhase adds the message of the boss phase which has just begun.
+func AnnounceBossPhase(dg *dungeon.Dungeon, boss *unit.Enemy) {
+	if message := boss.CurrentPhase().Message; message != "" {
+		dg.AddEventData(message)
+		return
+	}
+	dg.AddEventData(boss.Name() + " changes its tactics!")
+}
+
+// RemoveDeadMonsters removes dead enemies from the dungeon's enemy list, their treasure and loot drop on the map.
+// The death of the boss unseals the exit.
 func RemoveDeadMonsters(dg *dungeon.Dungeon) {
 	for i := len(dg.Enemies) - 1; i >= 0; i-- {
 		enemy := dg.Enemies[i].(*unit.Enemy)
 		if enemy.IsDead() {
 			dg.Enemies = append(dg.Enemies[:i], dg.Enemies[i+1:]...)
+			DropLoot(dg, enemy)
+			if enemy.IsBoss() {
+				dg.ExitSealed = false
+				dg.BossDefeated = true
+				dg.AddEventData("The exit is open!")
+			}
+		}
+	}
+}
+
+// DropLoot puts the treasure of the killed enemy as a pile of gold and its loot on the tile it died on,
+// the rest of the drops go to the nearest empty tiles.
+func DropLoot(dg *dungeon.Dungeon, enemy *unit.Enemy) {
+	drops := enemy.Loot
+	if enemy.Treasure > 0 {
+		drops = append([]item.Item{&item.Gold{Amount: enemy.Treasure}}, drops...)
+	}
+	var dropped []string
+	for _, it := range drops {
+		if dg.DropItemAt(it, enemy.Coords) {
+			dropped = append(dropped, it.Info())
 		}
 	}
+	if len(dropped) > 0 {
+		dg.AddEventData(enemy.Name() + " dropped " + strings.Join(dropped, ", ") + ".")
+	}
+	enemy.Treasure = 0
+	enemy.Loot = nil
 }
@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// EndPlayerTurn spends the energy of the player's action and runs the game time until the player can act again.
+// Every unit gains the energy of its speed every turn and acts whenever it has enough of it,
+// so a fast enemy acts several times between two actions of a slow player and a slow one skips turns.
+func EndPlayerTurn(dg *dungeon.Dungeon, combat unit.Combat) {
+	player := dg.Player.(*unit.Character)
+	player.SpendEnergy()
+	for !player.IsDead() {
+		EnemiesAct(dg, combat)
+		if player.Ready() || player.IsDead() {
+			return
+		}
+		NextTurn(dg)
+	}
+}
+
+// EnemiesAct lets the enemies with enough energy act. Enemies act in rounds of one action each,
+// in the initiative order, until none of them has energy for another action.
+// Every enemy engaged with the player gets its attack, so a surrounded player is hit by all of them.
+func EnemiesAct(dg *dungeon.Dungeon, combat unit.Combat) {
+	player := dg.Player.(*unit.Character)
+	for acted := true; acted && !player.IsDead(); {
+		acted = false
+		for _, enemy := range InitiativeOrder(dg.Enemies) {
+			if enemy.IsDead() || !enemy.Ready() {
+				continue
+			}
+			enemy.SpendEnergy()
+			EnemyAct(dg, enemy, combat)
+			acted = true
+			if player.IsDead() {
+				return
+			}
+		}
+	}
+}
+
+// InitiativeOrder returns the enemies in the order they act: the most agile first,
+// enemies of the same agility keep their order on the level.
+func InitiativeOrder(enemies []dungeon.Coordinator) []*unit.Enemy {
+	order := make([]*unit.Enemy, len(enemies))
+	for i := range enemies {
+		order[i] = enemies[i].(*unit.Enemy)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Agility > order[j].Agility })
+	return order
+}
+
+// EnemyAct makes the action of the enemy: an enemy next to the player fights it, the others move.
+// A sleeping enemy wastes its action.
+func EnemyAct(dg *dungeon.Dungeon, enemy *unit.Enemy, combat unit.Combat) {
+	UpdateFight(dg, enemy)
+	if !enemy.CanAct() {
+		return
+	}
+	if enemy.InBattle {
+		MonsterAttack(dg, enemy, combat)
+		return
+	}
+	enemy.Move(*dg)
+}
+
+// NextTurn begins a new turn of the game time: status effects and item effects tick,
+// then every unit gains the energy of its speed.
+func NextTurn(dg *dungeon.Dungeon) {
+	TickStatuses(dg)
+	RemoveDeadMonsters(dg)
+	UpdateItemsEffects(dg)
+	dg.Player.(*unit.Character).GainEnergy()
+	for i := range dg.Enemies {
+		dg.Enemies[i].(*unit.Enemy).GainEnergy()
+	}
+}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
+)
+
+// TickStatuses ticks the status effects of the player and the enemies at the start of a turn
+// and reports the player's ones: the health taken and the statuses which have worn off.
+func TickStatuses(dg *dungeon.Dungeon) {
+	player := dg.Player.(*unit.Character)
+	damage, expired := player.TickStatuses()
+	if damage > 0 {
+		dg.AddEventData(fmt.Sprintf("You lose %d health, you are %s!", damage, unit.StatusPoison))
+	}
+	for _, status := range expired {
+		dg.AddEventData(fmt.Sprintf("You are no longer %s.", status))
+	}
+	for i := range dg.Enemies {
+		dg.Enemies[i].(*unit.Enemy).TickStatuses()
+	}
+}
+
+// SkipsTurn checks if the player can't use the action, e.g. after a sleep trap or a snake wizard's hit.
+func SkipsTurn(dg *dungeon.Dungeon) bool {
+	player := dg.Player.(*unit.Character)
+	if player.CanAct() {
+		return false
+	}
+	dg.AddEventData("You are asleep...")
+	return true
+}
+
+// ConfusedDirection returns the direction the player really moves in: a random one if the player is confused.
+func ConfusedDirection(dir unit.Direction, dg *dungeon.Dungeon) unit.Direction {
+	player := dg.Player.(*unit.Character)
+	if !player.HasStatus(unit.StatusConfusion) {
+		return dir
+	}
+	confused := unit.Direction(dg.RNG.Intn(4))
+	if confused != dir {
+		dg.AddEventData("You are confused and stumble aside!")
+	}
+	return confused
+}
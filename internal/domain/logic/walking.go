@@ -1,6 +1,9 @@
 package logic
 
 import (
+	"fmt"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
@@ -8,10 +11,12 @@ import (
 
 // PlayerMove handle player's moving in the specified direction within the dungeon.
 // It checks if the move is possible before updating the player's coordinates.
+// A locked door on the way is opened first if the player has its key.
 // If the move is valid, the player's position is updated according to the given direction.
 func PlayerMove(dir unit.Direction, dg *dungeon.Dungeon) {
 	player := dg.Player.(*unit.Character)
 
+	OpenLockedDoor(GetCoordsAfterMoving(player.Coords, dir), dg)
 	if unit.IsPossiblePlayerMove(GetCoordsAfterMoving(player.Coords, dir), *dg) {
 		switch dir {
 		case unit.Up:
@@ -26,13 +31,116 @@ func PlayerMove(dir unit.Direction, dg *dungeon.Dungeon) {
 	}
 }
 
+// OpenLockedDoor unlocks the locked door at the coordinates if the player has its key.
+// The key is used up.
+func OpenLockedDoor(c common.Coords, dg *dungeon.Dungeon) {
+	lock := dg.DoorLock(c)
+	if lock == 0 {
+		return
+	}
+	player := dg.Player.(*unit.Character)
+	key, ok := player.Inventory.TakeKey(lock)
+	if !ok {
+		dg.AddEventData("The door is locked, find its key!")
+		return
+	}
+	dg.UnlockDoor(lock)
+	dg.AddEventData("You open the door with the " + key.Name + "!")
+}
+
+// CheckSealedExit tells the player on the sealed exit that the boss must be defeated first.
+func CheckSealedExit(dg *dungeon.Dungeon) {
+	if !dg.ExitSealed || dg.PlayerCoords() != dg.Exit {
+		return
+	}
+	for _, monster := range dg.Enemies {
+		if enemy, ok := monster.(*unit.Enemy); ok && enemy.IsBoss() {
+			dg.AddEventData("The exit is sealed, defeat " + enemy.Name() + " to open it!")
+			return
+		}
+	}
+	dg.AddEventData("The exit is sealed!")
+}
+
+// SearchSecrets looks for secret doors, corridors and traps next to the player and reports the result.
+func SearchSecrets(dg *dungeon.Dungeon) {
+	secrets := dg.Search()
+	traps := dg.DetectTraps()
+	if secrets > 0 {
+		dg.AddEventData("You found a secret passage!")
+	}
+	if traps > 0 {
+		dg.AddEventData("You found a trap!")
+	}
+	if secrets == 0 && traps == 0 {
+		dg.AddEventData("You search around but find nothing.")
+	}
+}
+
+// TriggerTrap sets off the trap under the player, the trap becomes visible.
+// A trapdoor only reveals itself here, the fall to the next level is handled like the exit.
+func TriggerTrap(dg *dungeon.Dungeon) {
+	trap := dg.TrapAt(dg.PlayerCoords())
+	if trap == nil {
+		return
+	}
+	trap.Revealed = true
+	player := dg.Player.(*unit.Character)
+	switch trap.Type {
+	case dungeon.TrapTeleport:
+		if c, ok := dg.TeleportDestination(); ok {
+			player.SetCoords(c)
+			dg.AddEventData("A teleport trap! You are somewhere else...")
+		}
+	case dungeon.TrapSleep:
+		player.AddStatus(unit.Status{Type: unit.StatusSleep, Duration: 1})
+		dg.AddEventData("A cloud of sleeping gas! You fall asleep.")
+	case dungeon.TrapArrow:
+		dmg := unit.ApplyDamage(&player.Unit, trap.Damage)
+		dg.AddEventData(fmt.Sprintf("An arrow trap! You lose %d health.", dmg))
+	case dungeon.TrapDoor:
+		dg.AddEventData("A trapdoor! You fall to the next level.")
+	}
+}
+
+// ReceiveBlessing gives the player the blessing of the shrine the player has entered.
+// The blessing raises an attribute permanently and fully heals the player; every shrine blesses once.
+func ReceiveBlessing(dg *dungeon.Dungeon) {
+	blessing, ok := dg.TakeBlessing()
+	if !ok {
+		return
+	}
+	player := dg.Player.(*unit.Character)
+	player.MaxHealth += blessing.MaxHealth
+	player.Strength += blessing.Strength
+	player.Agility += blessing.Agility
+	player.Health = player.MaxHealth
+
+	switch {
+	case blessing.MaxHealth > 0:
+		dg.AddEventData(fmt.Sprintf("The shrine blesses you: +%d max health!", blessing.MaxHealth))
+	case blessing.Strength > 0:
+		dg.AddEventData(fmt.Sprintf("The shrine blesses you: +%d strength!", blessing.Strength))
+	case blessing.Agility > 0:
+		dg.AddEventData(fmt.Sprintf("The shrine blesses you: +%d agility!", blessing.Agility))
+	default:
+		dg.AddEventData("The shrine heals your wounds!")
+	}
+}
+
 // CheckConsumables checks if the player is standing on any items in the dungeon
-// and attempts to add them to the player's inventory.
+// and attempts to add them to the player's inventory. Gold goes straight to the treasure.
 func CheckConsumables(dg *dungeon.Dungeon) {
 	player := dg.Player.(*unit.Character)
 	for i := len(dg.Items) - 1; i >= 0; i-- {
 		it := dg.Items[i]
 		if it.GetCoords() == dg.PlayerCoords() {
+			if gold, ok := it.(*item.Gold); ok {
+				player.TakeGold(gold)
+				dg.AddEventData("You pick up " + gold.Info() + "!")
+				dg.Items = append(dg.Items[:i], dg.Items[i+1:]...)
+				continue
+			}
 			if player.Inventory.Add(it) {
 				dg.AddEventData("You take the " + item.ItemsNames[it.Type()] + "!")
 				// !удаляется предмет из dg.Items, но должен ли?...
@@ -7,11 +7,14 @@ import (
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 )
 
-// Character represents the player character, including combat stats, weapon, and inventory.
+// Character represents the player character, including combat stats, weapon, armor and inventory.
 type Character struct {
 	Unit
 	MaxHealth     int
+	Level         int // experience level, from 1
+	XP            int // total experience received for killed enemies
 	CurrentWeapon *item.Weapon
+	CurrentArmor  *item.Armor
 	Inventory     inventory.Inventory
 	Stats         common.Stats
 }
@@ -49,6 +52,9 @@ var GoodPlayerTileSet = map[common.TileType]struct{}{
 	common.FinishTile:   {},
 	common.DoorTile:     {},
 	common.CorridorTile: {},
+	common.TrapTile:     {},
+	common.StairsUpTile: {},
+	common.ArtifactTile: {},
 }
 
 // IsWalkableForPlayer returns true if the given tile type is traversable by the player.
@@ -58,6 +64,7 @@ func IsWalkableForPlayer(t common.TileType) bool {
 }
 
 // IsPossiblePlayerMove checks if the player can move to the specified coordinates.
+// Locked doors are not walkable, they must be opened with a key first.
 func IsPossiblePlayerMove(c common.Coords, d dungeon.Dungeon) bool {
 	t, _ := d.Tile(c)
 	return IsWalkableForPlayer(t)
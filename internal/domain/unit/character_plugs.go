@@ -1,6 +1,7 @@
 package unit
 
 import (
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 )
@@ -53,11 +54,12 @@ func (ch *Character) EatFood(food *item.Food) {
 	ch.Inventory.Delete(food)
 }
 
-// DrinkElixir applies the elixir's temporary stat boosts to the character.
+// DrinkElixir applies the elixir's temporary stat boosts and speed change to the character.
 func (ch *Character) DrinkElixir(elixir item.Elixir) {
 	ch.MaxHealth += elixir.MaxHealth
 	ch.Agility += elixir.Agility
 	ch.Strength += elixir.Strength
+	ch.Speed += elixir.Speed
 	ch.Stats.ElixirsDrunk++
 }
 
@@ -73,24 +75,30 @@ func (ch *Character) UseScroll(scroll item.Scroll) {
 func (ch *Character) IsEnemyLeft() {
 }
 
-// HitEnemy attempts to hit an enemy and returns whether the hit was successful and the amount of gold earned.
-func (ch *Character) HitEnemy(enemy *Enemy) (bool, int) {
-	if IsHitSuccessful(&ch.Unit, &enemy.Unit) {
-		damage := CalculateDamage(ch)
-		ApplyDamage(&enemy.Unit, damage)
-
-		gold := 0
-		if enemy.IsDead() {
-			gold = enemy.Treasure
-			ch.Inventory.Treasure += gold
-			ch.Stats.TreasuresReceived += gold
-			ch.Stats.EnemiesDefeated++
-		}
-		ch.Stats.HitsMade++
-
-		return true, gold
+// HitEnemy attempts to hit an enemy by the combat formula and returns the outcome of the hit.
+// A killed enemy drops its treasure and loot, they are picked up from the map.
+func (ch *Character) HitEnemy(enemy *Enemy, rng *common.RNG, combat Combat) Hit {
+	if !combat.IsHitSuccessful(rng, &ch.Unit, &enemy.Unit) {
+		return Hit{}
+	}
+	hit := Hit{Landed: true}
+	var damage int
+	damage, hit.Critical = combat.RollDamage(rng, ch)
+	hit.Damage = ApplyDamage(&enemy.Unit, damage)
+
+	if enemy.IsDead() {
+		hit.Killed = true
+		hit.XP = enemy.XP
+		ch.Stats.EnemiesDefeated++
 	}
-	return false, 0
+	ch.Stats.HitsMade++
+	return hit
+}
+
+// TakeGold adds the gold of the pile to the treasure of the character.
+func (ch *Character) TakeGold(gold *item.Gold) {
+	ch.Inventory.Treasure += gold.Amount
+	ch.Stats.TreasuresReceived += gold.Amount
 }
 
 // ChooseWeapon equips a weapon if none is currently equipped.
@@ -109,9 +117,42 @@ func (ch *Character) DropWeapon(d *dungeon.Dungeon) bool {
 	weapon := ch.CurrentWeapon
 	ch.Strength -= weapon.Strength
 	ch.CurrentWeapon = nil
-	if d.AddItemToNearestPosition(*weapon) {
+	dropped := *weapon
+	if d.AddItemToNearestPosition(&dropped) {
 		ch.Inventory.Delete(weapon)
 		return true
 	}
 	return false
 }
+
+// ChooseArmor puts an armor on if none is currently worn.
+func (ch *Character) ChooseArmor(armor *item.Armor) {
+	if ch.CurrentArmor == nil {
+		ch.CurrentArmor = armor
+		ch.Defense += ch.CurrentArmor.Defense
+	}
+}
+
+// TakeOffArmor - put the worn armor back to the backpack
+func (ch *Character) TakeOffArmor() bool {
+	if ch.CurrentArmor == nil {
+		return false
+	}
+	ch.Defense -= ch.CurrentArmor.Defense
+	ch.CurrentArmor = nil
+	return true
+}
+
+// DropArmor - drop the worn armor to empty tile
+func (ch *Character) DropArmor(d *dungeon.Dungeon) bool {
+	armor := ch.CurrentArmor
+	if !ch.TakeOffArmor() {
+		return false
+	}
+	dropped := *armor
+	if d.AddItemToNearestPosition(&dropped) {
+		ch.Inventory.Delete(armor)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"math"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// Combat is the formula of the chance to hit and the damage of attacks, set by the combat section of the config.
+type Combat struct {
+	BaseHitChance  float64 // chance to hit a defender of the same agility
+	AgilityStep    float64 // change of the chance to hit per point of the agility difference
+	MinHitChance   float64 // the chance to hit is never lower
+	MaxHitChance   float64 // the chance to hit is never higher
+	DamageVariance float64 // share the damage varies by up and down, 0.25 gives 75%..125% of the base damage
+	CritChance     float64 // chance of a critical hit
+	CritMultiplier float64 // damage of a critical hit is multiplied by this value
+}
+
+// Hit is the outcome of an attack.
+type Hit struct {
+	Landed   bool // the attack has hit the defender
+	Critical bool // the hit is critical
+	Damage   int  // health taken from the defender, after its defense
+	Killed   bool // the defender has died of the hit
+	XP       int  // experience for the killed enemy
+}
+
+// ChanceToHit calculates the probability that an attacker hits the defender
+// based on their agility values. A blind attacker has half the chance.
+// The result is clamped between MinHitChance and MaxHitChance.
+func (c Combat) ChanceToHit(attacker, defender Unit) float64 {
+	base := c.BaseHitChance + float64(attacker.Agility-defender.Agility)*c.AgilityStep
+	if attacker.HasStatus(StatusBlindness) {
+		base /= 2
+	}
+	return math.Min(math.Max(base, c.MinHitChance), c.MaxHitChance)
+}
+
+// IsHitSuccessful determines whether an attacker's hit attempt succeeds
+// taking into account the defender's evasion, which is used up by the hit, and a random roll.
+func (c Combat) IsHitSuccessful(rng *common.RNG, attacker, defender *Unit) bool {
+	if defender.UseStatus(StatusEvasion) {
+		return false
+	}
+	return rng.Float64() < c.ChanceToHit(*attacker, *defender)
+}
+
+// RollDamage computes the damage of a landed hit: the base damage of the attacker varied randomly
+// within DamageVariance, multiplied by CritMultiplier on a critical hit. A hit deals at least 1 damage.
+func (c Combat) RollDamage(rng *common.RNG, attacker Fighter) (damage int, critical bool) {
+	varied := float64(CalculateDamage(attacker)) * (1 + c.DamageVariance*(2*rng.Float64()-1))
+	critical = rng.Float64() < c.CritChance
+	if critical {
+		varied *= c.CritMultiplier
+	}
+	return max(1, int(math.Round(varied))), critical
+}
@@ -1,76 +1,49 @@
 package unit
 
 import (
-	"math/rand"
-
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/item"
 )
 
-// ApplyEffect make some battle effects from Enemies
-func (e *Enemy) ApplyEffect(target Fighter) {
-	switch e.EnemyType {
-	case Vampire:
-		if player, ok := target.(*Character); ok {
-			stolenHealth := common.RandomInRange(MinTakenMaxHealth, MaxTakenMaxHealth)
-			player.MaxHealth -= stolenHealth
-			if player.MaxHealth < 0 {
-				player.MaxHealth = 0
-			}
-			if player.Health > player.MaxHealth {
-				player.Health = player.MaxHealth
-			}
+// ApplyEffect works the on-hit effects of the enemy's kind on the player, each by its chance.
+func (e *Enemy) ApplyEffect(target Fighter, rng *common.RNG) {
+	player, ok := target.(*Character)
+	if !ok {
+		return
+	}
+	for _, hit := range e.Kind.OnHit {
+		effect, err := OnHitEffectByName(hit.Effect)
+		if err != nil {
+			continue
 		}
-	case SnakeWizard:
-		if rand.Float64() < 0.3 {
-			if player, ok := target.(*Character); ok {
-				player.SkipNextTurn = true
-			}
+		if hit.Chance < 100 && rng.Intn(100) >= hit.Chance {
+			continue
 		}
-	default:
-		// TODO тут допустим логирование для неизвестного врага
+		effect(player, hit, rng)
 	}
 }
 
-// EnemyType defines the type of enemy.
-type EnemyType int
-
-// EnemyType represents different categories of enemies in the game.
-// The behavior and movement patterns vary by enemy type.
-const (
-	Zombie      EnemyType = iota // Slow-moving, basic enemy
-	Vampire                      // Fast-moving, may have special abilities
-	Ghost                        // Can teleport and become invisible
-	Ogr                          // Strong but slow enemy
-	SnakeWizard                  // Moves diagonally with magical abilities
-)
-
-// EnemyNames provides a string values for all of enemy types.
-var EnemyNames = map[EnemyType]string{
-	Zombie:      "Zombie",
-	Vampire:     "Vampire",
-	Ghost:       "Ghost",
-	Ogr:         "Ogr",
-	SnakeWizard: "Snake-Wizard",
-}
-
 // SetCoords for enemy
 func (e *Enemy) SetCoords(c common.Coords) {
 	e.Coords.X = c.X
 	e.Coords.Y = c.Y
 }
 
-// Enemy represents an enemy entity with stats, type, hostility, visibility, and movement behavior.
+// Enemy represents an enemy entity with stats, kind, hostility, visibility, and movement behavior.
 type Enemy struct {
 	Unit
-	EnemyType
-	Animosity    int        // How aggressive the enemy is toward the player.
-	Visibility   bool       // Used for ghost invisibility logic.
-	Mover        EnemyMover // current move pattern
-	DefaultMover EnemyMover // for switch from Pursuing
-	IsPursuing   bool       // Move toward to Character if Enemy noticed him
-	Treasure     int        // Number of treasurre which Character will receive after kill the monster
+	Kind         EnemyKind   // definition of the enemy: name, look, movement and special rules
+	Animosity    int         // How aggressive the enemy is toward the player.
+	Visibility   bool        // Used for invisibility logic.
+	Mover        EnemyMover  // current move pattern
+	DefaultMover EnemyMover  // for switch from Pursuing
+	IsPursuing   bool        // Move toward to Character if Enemy noticed him
+	Treasure     int         // Gold dropped as a pile when the monster is killed
+	Loot         []item.Item // Items dropped when the monster is killed, rolled by the loot table of its kind
+	XP           int         // Experience which Character will receive after kill the monster
+	Phases       []BossPhase // phases of the boss fight, empty for other enemies
+	Phase        int         // index of the current boss phase
 }
 
 // TreasureFactors define proportions used in treasure generation calculations.
@@ -81,58 +54,33 @@ const (
 	TreasureFactorHealth   = 0.2
 )
 
-// NewEnemy creates a new enemy of the specified type at given coordinates.
-func NewEnemy(t EnemyType, x, y int) *Enemy {
+// NewEnemy creates a new enemy of the kind without placing on the field.
+func NewEnemy(kind EnemyKind) *Enemy {
 	e := &Enemy{
-		Unit: Unit{
-			Coords: common.Coords{X: x, Y: y},
-		},
-		EnemyType:  t,
+		Kind:       kind,
 		Visibility: true,
 		IsPursuing: Chilling,
 	}
-
-	switch t {
-	case Ghost:
-		e.DefaultMover = GhostMoving{}
-	case SnakeWizard:
-		e.DefaultMover = SnakeWizardMoving{}
-	case Ogr:
-		e.DefaultMover = OgrMoving{}
-	default:
-		e.DefaultMover = NonMoving{}
-	}
-
+	e.DefaultMover = e.KindMover()
 	e.Mover = e.DefaultMover
-
 	return e
 }
 
-// NewEnemyWithoutCoords create new enemy without placing on the field
-func NewEnemyWithoutCoords(t EnemyType) *Enemy {
-	e := &Enemy{
-		Unit: Unit{
-			Coords: common.Coords{},
-		},
-		EnemyType:  t,
-		Visibility: true,
-		IsPursuing: Chilling,
-	}
+// Name returns the title of the enemy shown in the game messages.
+func (e *Enemy) Name() string {
+	return e.Kind.Title
+}
 
-	switch t {
-	case Ghost:
-		e.DefaultMover = GhostMoving{}
-	case SnakeWizard:
-		e.DefaultMover = SnakeWizardMoving{}
-	case Ogr:
-		e.DefaultMover = OgrMoving{}
-	default:
-		e.DefaultMover = NonMoving{}
+// KindMover returns the default movement of the enemy: the movement of its kind,
+// standing still if the kind's movement is unknown, the behaviour of the current phase for the boss.
+func (e *Enemy) KindMover() EnemyMover {
+	if e.IsBoss() {
+		return BossMoving{}
 	}
-
-	e.Mover = e.DefaultMover
-
-	return e
+	if mover, err := EnemyMoverByName(e.Kind.Mover); err == nil {
+		return mover
+	}
+	return NonMoving{}
 }
 
 // CurrentStrength returns the enemy's current strength value.
@@ -170,19 +118,21 @@ func (e *Enemy) GetCoords() common.Coords {
 	return e.Unit.Coords
 }
 
-// HitCharacter attempts to hit a character, with a guaranteed hit for Ogr enemies.
-// It calculates and applies damage if the hit is successful, and applies any enemy-specific effects.
-// Returns a boolean indicating hit success and a placeholder value (currently always 0).
-func (e *Enemy) HitCharacter(character *Character) bool {
-	if IsHitSuccessful(&e.Unit, &character.Unit) || e.EnemyType == Ogr {
-		damage := CalculateDamage(e)
-		if e.EnemyType != Vampire {
-			ApplyDamage(&character.Unit, damage)
-		}
-		e.ApplyEffect(character)
-		character.Stats.HitsMissed++
-
-		return true
+// HitCharacter attempts to hit a character by the combat formula, with a guaranteed hit for the sure-hitting kinds.
+// It applies damage if the hit is successful, and applies the on-hit effects of the kind.
+// A kind without damage works only by its effects. Returns the outcome of the hit.
+func (e *Enemy) HitCharacter(character *Character, rng *common.RNG, combat Combat) Hit {
+	if !combat.IsHitSuccessful(rng, &e.Unit, &character.Unit) && !e.HasTrait(TraitSureHit) {
+		return Hit{}
+	}
+	hit := Hit{Landed: true}
+	if !e.HasTrait(TraitNoDamage) {
+		var damage int
+		damage, hit.Critical = combat.RollDamage(rng, e)
+		hit.Damage = ApplyDamage(&character.Unit, damage)
 	}
-	return false
+	e.ApplyEffect(character, rng)
+	hit.Killed = character.IsDead()
+	character.Stats.HitsMissed++
+	return hit
 }
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
+)
+
+// BossBehaviour defines how the boss moves during a phase.
+type BossBehaviour int
+
+const (
+	// BossGuard stays in place and fights whoever comes close
+	BossGuard BossBehaviour = iota
+	// BossPursue chases the player
+	BossPursue
+	// BossBlink teleports next to the player when they are in the same room
+	BossBlink
+)
+
+// ErrUnknownBossBehaviour is returned when there's no boss behaviour with the requested name.
+var ErrUnknownBossBehaviour = errors.New("unknown boss behaviour")
+
+// bossBehaviourNames contains the boss behaviours by their config names
+var bossBehaviourNames = map[string]BossBehaviour{
+	"guard":  BossGuard,
+	"pursue": BossPursue,
+	"blink":  BossBlink,
+}
+
+// BossBehaviourByName returns the boss behaviour with the given config name.
+func BossBehaviourByName(name string) (BossBehaviour, error) {
+	behaviour, ok := bossBehaviourNames[name]
+	if !ok {
+		return BossGuard, fmt.Errorf("%w: %q", ErrUnknownBossBehaviour, name)
+	}
+	return behaviour, nil
+}
+
+// BossBehaviourNames returns sorted names of all boss behaviours.
+func BossBehaviourNames() []string {
+	names := make([]string, 0, len(bossBehaviourNames))
+	for name := range bossBehaviourNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BossPhase is a stage of the boss fight with its own stats and behaviour.
+// A phase begins when the boss health falls to its Health value.
+type BossPhase struct {
+	Health    int // the phase begins when the boss health falls to this value or lower
+	Strength  int
+	Agility   int
+	Behaviour BossBehaviour
+	Message   string // shown when the phase begins
+}
+
+// NewBoss creates the boss with the given phases, the first phase is active from the start.
+// The phases must be sorted by Health from the highest.
+func NewBoss(kind EnemyKind, health, animosity, treasure, xp int, phases []BossPhase) *Enemy {
+	e := NewEnemy(kind)
+	e.Phases = phases
+	e.DefaultMover = e.KindMover()
+	e.Mover = e.DefaultMover
+	e.Health = health
+	e.Animosity = animosity
+	e.Treasure = treasure
+	e.XP = xp
+	e.Strength = phases[0].Strength
+	e.Agility = phases[0].Agility
+	return e
+}
+
+// IsBoss checks if the enemy is the boss, which fights in phases.
+func (e *Enemy) IsBoss() bool {
+	return len(e.Phases) > 0
+}
+
+// CurrentPhase returns the active phase of the boss.
+func (e *Enemy) CurrentPhase() BossPhase {
+	return e.Phases[e.Phase]
+}
+
+// NextPhase switches the living boss to the latest phase its health has fallen to.
+// A strong hit may skip a phase. Returns true if the phase has changed.
+func (e *Enemy) NextPhase() bool {
+	if !e.IsBoss() || e.IsDead() {
+		return false
+	}
+	changed := false
+	for e.Phase+1 < len(e.Phases) && e.Health <= e.Phases[e.Phase+1].Health {
+		e.Phase++
+		changed = true
+	}
+	if changed {
+		e.Strength = e.Phases[e.Phase].Strength
+		e.Agility = e.Phases[e.Phase].Agility
+	}
+	return changed
+}
+
+// BossMoving implements EnemyMover for the boss, it moves by the behaviour of the current phase.
+type BossMoving struct{}
+
+// Move moves the boss by the behaviour of its current phase.
+// A blinking boss which can't land next to the player walks toward them instead.
+func (s BossMoving) Move(e *Enemy, d dungeon.Dungeon) {
+	switch e.CurrentPhase().Behaviour {
+	case BossPursue:
+		stepToPlayer(e, d)
+	case BossBlink:
+		if !blinkToPlayer(e, d) {
+			stepToPlayer(e, d)
+		}
+	}
+}
+
+// stepToPlayer moves the enemy one step along the shortest path toward the player.
+func stepToPlayer(e *Enemy, d dungeon.Dungeon) {
+	path, _ := e.FindPathToPlayer(d)
+	if len(path) > 0 && path[0] != d.PlayerCoords() {
+		e.SetCoords(path[0])
+	}
+}
+
+// blinkToPlayer teleports the enemy to a random free floor tile next to the player in the same room.
+// Returns false if the player isn't in the enemy's room or there's no free tile around.
+func blinkToPlayer(e *Enemy, d dungeon.Dungeon) bool {
+	r := FindRoomByCoords(e.GetCoords(), d.Rooms[:])
+	player := d.PlayerCoords()
+	if r == nil || !dungeon.IsCoordInRoom(player, *r) {
+		return false
+	}
+	var free []common.Coords
+	for _, c := range []common.Coords{{X: player.X + 1, Y: player.Y}, {X: player.X - 1, Y: player.Y}, {X: player.X, Y: player.Y + 1}, {X: player.X, Y: player.Y - 1}} {
+		if isPossibleEnemyMove(c, *r, d) {
+			free = append(free, c)
+		}
+	}
+	if len(free) == 0 {
+		return false
+	}
+	e.SetCoords(free[d.RNG.Intn(len(free))])
+	return true
+}
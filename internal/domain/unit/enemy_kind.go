@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
+)
+
+// EnemyKind is the definition of a kind of enemies, set by the enemies section of the config.
+// Every enemy carries its kind, so a saved enemy keeps working if the config changes.
+type EnemyKind struct {
+	Name   string        // config name, e.g. "zombie"
+	Title  string        // shown in the game messages, e.g. "Zombie"
+	Glyph  rune          // letter on the game screen and in ASCII maps
+	Colour common.Colour // colour on the game screen
+	Mover  string        // name of the default movement, one of EnemyMoverNames
+	Traits []Trait       // special rules of the kind
+	OnHit  []OnHit       // effects of the landed hits on the player
+}
+
+// Trait is a special rule of an enemy kind.
+type Trait int
+
+const (
+	// TraitSureHit makes the hits of the enemy never miss
+	TraitSureHit Trait = iota
+	// TraitRests wakes the enemy up when a battle starts and makes it rest for a turn after every blow
+	TraitRests
+	// TraitEvasive makes the first hit against the enemy in every battle miss
+	TraitEvasive
+	// TraitNoDamage makes the hits of the enemy deal no damage, they work only by the on-hit effects
+	TraitNoDamage
+	// TraitInvisible makes the enemy invisible half the time while it moves
+	TraitInvisible
+	// TraitDiagonalReach lets the enemy attack diagonally too
+	TraitDiagonalReach
+)
+
+// ErrUnknownTrait is returned when there's no trait with the requested name.
+var ErrUnknownTrait = errors.New("unknown enemy trait")
+
+// traitNames contains the traits by their config names
+var traitNames = map[string]Trait{
+	"sure_hit":       TraitSureHit,
+	"rests":          TraitRests,
+	"evasive":        TraitEvasive,
+	"no_damage":      TraitNoDamage,
+	"invisible":      TraitInvisible,
+	"diagonal_reach": TraitDiagonalReach,
+}
+
+// TraitByName returns the trait with the given config name.
+func TraitByName(name string) (Trait, error) {
+	trait, ok := traitNames[name]
+	if !ok {
+		return TraitSureHit, fmt.Errorf("%w: %q", ErrUnknownTrait, name)
+	}
+	return trait, nil
+}
+
+// Name returns the config name of the trait.
+func (t Trait) Name() string {
+	for name, trait := range traitNames {
+		if trait == t {
+			return name
+		}
+	}
+	return ""
+}
+
+// TraitNames returns sorted names of all traits.
+func TraitNames() []string {
+	names := make([]string, 0, len(traitNames))
+	for name := range traitNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasTrait checks if the enemy's kind has the trait.
+func (e *Enemy) HasTrait(t Trait) bool {
+	for _, trait := range e.Kind.Traits {
+		if trait == t {
+			return true
+		}
+	}
+	return false
+}
+
+// OnHit is an effect of the enemy's landed hit on the player.
+type OnHit struct {
+	Effect   string // name of the effect, one of OnHitEffectNames
+	Chance   int    // chance in percent the effect works on a landed hit
+	Duration int    // turns of a status effect
+	Power    [2]int // strength of the effect [min, max], e.g. max health drained or poison taken every turn
+}
+
+// OnHitEffect works an on-hit effect on the player.
+type OnHitEffect func(target *Character, hit OnHit, rng *common.RNG)
+
+// ErrUnknownOnHitEffect is returned when there's no on-hit effect with the requested name.
+var ErrUnknownOnHitEffect = errors.New("unknown on-hit effect")
+
+// onHitEffects contains the effects which aren't statuses by their config names.
+// Every status name is an on-hit effect too, it puts the status on the player.
+var onHitEffects = map[string]OnHitEffect{
+	"drain_max_health": drainMaxHealth,
+}
+
+// OnHitEffectByName returns the on-hit effect with the given config name.
+func OnHitEffectByName(name string) (OnHitEffect, error) {
+	if effect, ok := onHitEffects[name]; ok {
+		return effect, nil
+	}
+	status, err := StatusTypeByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOnHitEffect, name)
+	}
+	return func(target *Character, hit OnHit, rng *common.RNG) {
+		target.AddStatus(Status{
+			Type:     status,
+			Duration: hit.Duration,
+			Power:    common.RandomInRange(rng, hit.Power[0], hit.Power[1]),
+		})
+	}, nil
+}
+
+// OnHitEffectNames returns sorted names of all on-hit effects, including the statuses.
+func OnHitEffectNames() []string {
+	names := StatusNames()
+	for name := range onHitEffects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// drainMaxHealth takes max health from the player, the health doesn't stay above the max health.
+func drainMaxHealth(player *Character, hit OnHit, rng *common.RNG) {
+	player.MaxHealth = max(0, player.MaxHealth-common.RandomInRange(rng, hit.Power[0], hit.Power[1]))
+	player.Health = min(player.Health, player.MaxHealth)
+}
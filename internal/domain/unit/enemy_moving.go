@@ -1,6 +1,10 @@
 package unit
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 )
@@ -25,16 +29,72 @@ type EnemyMover interface {
 	Move(e *Enemy, d dungeon.Dungeon)
 }
 
+// ErrUnknownMover is returned when there's no enemy movement with the requested name.
+var ErrUnknownMover = errors.New("unknown enemy movement")
+
+// enemyMovers contains the default movements of enemy kinds by their config names.
+// Pursuing and the boss behaviours aren't default movements, enemies switch to them by themselves.
+var enemyMovers = map[string]EnemyMover{
+	"still":    NonMoving{},
+	"teleport": GhostMoving{},
+	"diagonal": SnakeWizardMoving{},
+	"leap":     OgrMoving{},
+}
+
+// EnemyMoverByName returns the enemy movement with the given config name.
+func EnemyMoverByName(name string) (EnemyMover, error) {
+	mover, ok := enemyMovers[name]
+	if !ok {
+		return NonMoving{}, fmt.Errorf("%w: %q", ErrUnknownMover, name)
+	}
+	return mover, nil
+}
+
+// EnemyMoverNames returns sorted names of all enemy movements.
+func EnemyMoverNames() []string {
+	names := make([]string, 0, len(enemyMovers))
+	for name := range enemyMovers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flicker makes an invisible enemy visible or invisible at random.
+func (e *Enemy) flicker(d dungeon.Dungeon) {
+	if e.HasTrait(TraitInvisible) {
+		e.Visibility = common.RandomBool(d.RNG)
+	}
+}
+
 // Move executes the enemy's current movement strategy after updating its mode.
 // It first calls SetMovingMode to determine the appropriate movement strategy,
 // then invokes the mover's Move method if a strategy is set.
+// A confused enemy stumbles to a random free tile of its room instead.
 func (e *Enemy) Move(d dungeon.Dungeon) {
+	if e.HasStatus(StatusConfusion) {
+		e.stumble(d)
+		return
+	}
 	if e.Mover != nil {
 		e.SetMovingMode(d)
 		e.Mover.Move(e, d)
 	}
 }
 
+// stumble moves the enemy one step in a random direction if the tile there is free.
+func (e *Enemy) stumble(d dungeon.Dungeon) {
+	r := FindRoomByCoords(e.GetCoords(), d.Rooms[:])
+	if r == nil {
+		return
+	}
+	c := e.GetCoords()
+	steps := []common.Coords{{X: c.X + 1, Y: c.Y}, {X: c.X - 1, Y: c.Y}, {X: c.X, Y: c.Y + 1}, {X: c.X, Y: c.Y - 1}}
+	if step := steps[d.RNG.Intn(len(steps))]; isPossibleEnemyMove(step, *r, d) {
+		e.SetCoords(step)
+	}
+}
+
 // InRoomWithPlayer checks if both the enemy and player are in the same room.
 func (e *Enemy) InRoomWithPlayer(d dungeon.Dungeon) bool {
 	p := d.PlayerCoords()
@@ -50,7 +110,13 @@ func (e *Enemy) InRoomWithPlayer(d dungeon.Dungeon) bool {
 // - Current location (room/corridor)
 // - Path availability
 // - Animosity (pursuit range)
+//
+// The boss always moves by the behaviour of its current phase.
 func (e *Enemy) SetMovingMode(d dungeon.Dungeon) {
+	if e.IsBoss() {
+		e.Mover = e.DefaultMover
+		return
+	}
 	c, _ := d.TileUnderEnemy(e.GetCoords())
 	_, path := e.FindPathToPlayer(d)
 	shouldPursue := path != -1 && path <= e.Animosity
@@ -72,6 +138,7 @@ func (e *Enemy) SetMovingMode(d dungeon.Dungeon) {
 }
 
 // FindPathToPlayer calculates the shortest path to the player using BFS.
+// Enemies can't open locked doors, so the path never goes through them.
 // Returns the path (excluding current position) and its length.
 // Returns empty slice and -1 if no path exists.
 func (e *Enemy) FindPathToPlayer(d dungeon.Dungeon) ([]common.Coords, int) {
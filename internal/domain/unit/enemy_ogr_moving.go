@@ -17,7 +17,7 @@ func (s OgrMoving) Move(e *Enemy, d dungeon.Dungeon) {
 	}
 	maxAttempts := 10
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		dir := common.RandomInRange(int(Down), int(Right))
+		dir := common.RandomInRange(d.RNG, int(Down), int(Right))
 		var moved bool
 		switch dir {
 		case int(DownLeft):
@@ -1,7 +1,6 @@
 package unit
 
 import (
-	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 )
 
@@ -12,9 +11,7 @@ type PursuingMoving struct{}
 // If no path exists, falls back to the enemy's default movement strategy.
 func (p PursuingMoving) Move(e *Enemy, d dungeon.Dungeon) {
 	path, _ := e.FindPathToPlayer(d)
-	if e.EnemyType == Ghost {
-		e.Visibility = common.RandomBool()
-	}
+	e.flicker(d)
 	if len(path) > 0 {
 		nextPos := path[0]
 		if d.PlayerCoords() != nextPos {
@@ -17,7 +17,7 @@ func (s SnakeWizardMoving) Move(e *Enemy, d dungeon.Dungeon) {
 	}
 	maxAttempts := 10
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		dir := common.RandomInRange(int(DownLeft), int(DownRight))
+		dir := common.RandomInRange(d.RNG, int(DownLeft), int(DownRight))
 		var moved bool
 		switch dir {
 		case int(DownLeft):
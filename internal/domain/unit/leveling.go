@@ -0,0 +1,35 @@
+package unit
+
+// Leveling is the progression of the player, set by the leveling section of the config.
+// Every next level needs BaseXP more experience than the previous one: the second level needs BaseXP,
+// the third one 2*BaseXP more and so on.
+type Leveling struct {
+	BaseXP    int // experience needed for the second level
+	MaxHealth int // max health gained with every level, health grows by the same value
+	Strength  int // strength gained with every level
+	Agility   int // agility gained with every level
+}
+
+// XPForLevel returns the total experience needed to reach the level.
+func (l Leveling) XPForLevel(level int) int {
+	return l.BaseXP * level * (level - 1) / 2
+}
+
+// GainXP adds the experience to the character and raises its level as long as the experience is enough
+// for the next one. Returns the number of levels gained.
+func (ch *Character) GainXP(xp int, l Leveling) int {
+	ch.XP += xp
+	if l.BaseXP <= 0 {
+		return 0
+	}
+	gained := 0
+	for ch.XP >= l.XPForLevel(ch.Level+1) {
+		ch.Level++
+		ch.MaxHealth += l.MaxHealth
+		ch.Health += l.MaxHealth
+		ch.Strength += l.Strength
+		ch.Agility += l.Agility
+		gained++
+	}
+	return gained
+}
@@ -0,0 +1,35 @@
+package unit
+
+const (
+	// NormalSpeed is the speed of a unit which acts once every turn
+	NormalSpeed = 100
+	// ActionEnergy is the energy an action costs, a unit acts when it has accumulated that much
+	ActionEnergy = NormalSpeed
+	// MinSpeed is the lowest speed of a unit, so even the slowest unit acts sometimes
+	MinSpeed = 10
+)
+
+// CurrentSpeed returns the unit's speed with the status effects: slowness halves it.
+// It never falls below MinSpeed.
+func (u *Unit) CurrentSpeed() int {
+	speed := u.Speed
+	if u.HasStatus(StatusSlowness) {
+		speed /= 2
+	}
+	return max(MinSpeed, speed)
+}
+
+// GainEnergy adds the energy of one turn to the unit: the faster the unit, the more energy it gets.
+func (u *Unit) GainEnergy() {
+	u.Energy += u.CurrentSpeed()
+}
+
+// Ready checks if the unit has accumulated enough energy for an action.
+func (u *Unit) Ready() bool {
+	return u.Energy >= ActionEnergy
+}
+
+// SpendEnergy takes the energy of an action from the unit.
+func (u *Unit) SpendEnergy() {
+	u.Energy -= ActionEnergy
+}
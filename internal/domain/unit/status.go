@@ -0,0 +1,186 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// StatusType identifies a status effect of a unit
+type StatusType int
+
+const (
+	// StatusSleep makes the unit skip its turns
+	StatusSleep StatusType = iota
+	// StatusEvasion makes the next hit against the unit miss, it's used up by that hit
+	StatusEvasion
+	// StatusPoison takes Power health from the unit every turn
+	StatusPoison
+	// StatusConfusion makes the unit move in a random direction
+	StatusConfusion
+	// StatusBlindness halves the unit's chance to hit
+	StatusBlindness
+	// StatusSlowness halves the unit's speed
+	StatusSlowness
+)
+
+// Stacking defines what happens when a unit gets a status it already has.
+type Stacking int
+
+const (
+	// StackRefresh keeps the longer of the two durations
+	StackRefresh Stacking = iota
+	// StackExtend adds the new duration to the left one
+	StackExtend
+	// StackIntensify adds the powers up and keeps the longer duration
+	StackIntensify
+)
+
+// UntilUsed is the duration of a status which doesn't wear off with turns and lasts until it's used up.
+const UntilUsed = -1
+
+// Status is a status effect on a unit.
+type Status struct {
+	Type     StatusType
+	Duration int // turns left, UntilUsed for a status which is used up by its effect
+	Power    int // strength of the effect, e.g. health taken by poison every turn
+}
+
+// statusKind describes the behaviour shared by all statuses of a type.
+type statusKind struct {
+	name     string
+	label    string // shown while the status is active, e.g. "poisoned"
+	stacking Stacking
+	tick     func(u *Unit, s Status) // per-turn hook, nil if the status does nothing by itself
+}
+
+// statusKinds contains the behaviour of every status type.
+var statusKinds = map[StatusType]statusKind{
+	StatusSleep:     {name: "sleep", label: "asleep", stacking: StackRefresh},
+	StatusEvasion:   {name: "evasion", label: "evasive", stacking: StackRefresh},
+	StatusPoison:    {name: "poison", label: "poisoned", stacking: StackIntensify, tick: poisonTick},
+	StatusConfusion: {name: "confusion", label: "confused", stacking: StackExtend},
+	StatusBlindness: {name: "blindness", label: "blind", stacking: StackRefresh},
+	StatusSlowness:  {name: "slowness", label: "slowed", stacking: StackExtend},
+}
+
+// ErrUnknownStatus is returned when there's no status type with the requested name.
+var ErrUnknownStatus = errors.New("unknown status")
+
+// StatusTypeByName returns the status type with the given config name.
+func StatusTypeByName(name string) (StatusType, error) {
+	for t, kind := range statusKinds {
+		if kind.name == name {
+			return t, nil
+		}
+	}
+	return StatusSleep, fmt.Errorf("%w: %q", ErrUnknownStatus, name)
+}
+
+// StatusNames returns sorted names of all status types.
+func StatusNames() []string {
+	names := make([]string, 0, len(statusKinds))
+	for _, kind := range statusKinds {
+		names = append(names, kind.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns the label of the status shown to the player, e.g. "poisoned".
+func (t StatusType) String() string {
+	return statusKinds[t].label
+}
+
+// poisonTick takes the poison power from the unit's health. Armor doesn't protect from poison.
+func poisonTick(u *Unit, s Status) {
+	u.Health = max(0, u.Health-s.Power)
+}
+
+// AddStatus puts the status on the unit. A status the unit already has is stacked by the rule of its type.
+func (u *Unit) AddStatus(s Status) {
+	for i := range u.Statuses {
+		old := &u.Statuses[i]
+		if old.Type != s.Type {
+			continue
+		}
+		if old.Duration == UntilUsed || s.Duration == UntilUsed {
+			old.Duration = UntilUsed
+			return
+		}
+		switch statusKinds[s.Type].stacking {
+		case StackExtend:
+			old.Duration += s.Duration
+		case StackIntensify:
+			old.Power += s.Power
+			old.Duration = max(old.Duration, s.Duration)
+		default:
+			old.Duration = max(old.Duration, s.Duration)
+		}
+		return
+	}
+	u.Statuses = append(u.Statuses, s)
+}
+
+// HasStatus checks if the unit has the status.
+func (u *Unit) HasStatus(t StatusType) bool {
+	_, ok := u.status(t)
+	return ok
+}
+
+// status returns the unit's status of the type.
+func (u *Unit) status(t StatusType) (Status, bool) {
+	for _, s := range u.Statuses {
+		if s.Type == t {
+			return s, true
+		}
+	}
+	return Status{}, false
+}
+
+// RemoveStatus takes the status off the unit.
+func (u *Unit) RemoveStatus(t StatusType) {
+	for i, s := range u.Statuses {
+		if s.Type == t {
+			u.Statuses = append(u.Statuses[:i], u.Statuses[i+1:]...)
+			return
+		}
+	}
+}
+
+// UseStatus takes the status off the unit if it has one. Returns true if the status has been used.
+func (u *Unit) UseStatus(t StatusType) bool {
+	if !u.HasStatus(t) {
+		return false
+	}
+	u.RemoveStatus(t)
+	return true
+}
+
+// TickStatuses runs at the start of every turn of the unit: statuses whose duration is out wear off,
+// the others work their per-turn hooks and their durations go down.
+// Returns the health taken by the hooks and the statuses which have worn off.
+func (u *Unit) TickStatuses() (damage int, expired []StatusType) {
+	health := u.Health
+	kept := u.Statuses[:0]
+	for _, s := range u.Statuses {
+		if s.Duration == 0 {
+			expired = append(expired, s.Type)
+			continue
+		}
+		if tick := statusKinds[s.Type].tick; tick != nil {
+			tick(u, s)
+		}
+		if s.Duration != UntilUsed {
+			s.Duration--
+		}
+		kept = append(kept, s)
+	}
+	u.Statuses = kept
+	return health - u.Health, expired
+}
+
+// CanAct checks if the unit can use its action: a sleeping unit can't.
+func (u *Unit) CanAct() bool {
+	return !u.HasStatus(StatusSleep)
+}
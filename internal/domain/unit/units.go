@@ -1,41 +1,21 @@
 package unit
 
 import (
-	"math/rand"
-
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 )
 
-const (
-	// BaseAgility is the baseline chance to hit before agility difference adjustment.
-	BaseAgility = 0.5
-
-	// MinAgility is the minimum chance to hit.
-	MinAgility = 0.1
-
-	// MaxAgility is the maximum chance to hit.
-	MaxAgility = 0.9
-
-	// AgilityStep is the multiplier applied to the agility difference to adjust chance to hit.
-	AgilityStep = 0.05
-
-	// MinTakenMaxHealth Vampire can take some max health - min value
-	MinTakenMaxHealth = 1
-
-	// MaxTakenMaxHealth Vampire can take some max health - max value
-	MaxTakenMaxHealth = 3
-)
-
 // Unit represents a generic entity with health, agility, strength, and coordinates.
 type Unit struct {
-	Health       int
-	Agility      int
-	Strength     int
-	Coords       common.Coords
-	InBattle     bool
-	FirstHitMiss bool // if Unit is a Vampire - Character miss first hit
-	SkipNextTurn bool // for Character - wizard can throw him in a sleep, for Enemy - ogr skip every second attack
+	Health   int
+	Agility  int
+	Strength int
+	Defense  int // damage absorbed from every hit, given by the worn armor
+	Speed    int // energy gained every turn, NormalSpeed for a unit acting once a turn
+	Energy   int // energy accumulated for the next action
+	Coords   common.Coords
+	InBattle bool
+	Statuses []Status // active status effects, e.g. sleep after a snake wizard's hit or a vampire's evasion
 }
 
 // GetCoords - get current unit's position
@@ -63,29 +43,7 @@ type Fighter interface {
 	// WeaponInHands() *item.Weapon
 }
 
-// ChanceToHit calculates the probability that an attacker hits the defender
-// based on their agility values. The result is clamped between MinAgility and MaxAgility.
-func ChanceToHit(attacker, defender Unit) float64 {
-	base := BaseAgility + float64(attacker.Agility-defender.Agility)*AgilityStep
-	if base < MinAgility {
-		base = MinAgility
-	} else if base > MaxAgility {
-		base = MaxAgility
-	}
-	return base
-}
-
-// IsHitSuccessful determines whether an attacker's hit attempt succeeds
-// taking into account the special first-hit miss condition and a random roll.
-func IsHitSuccessful(attacker, defender *Unit) bool {
-	if defender.FirstHitMiss {
-		defender.FirstHitMiss = false
-		return false
-	}
-	return rand.Float64() < ChanceToHit(*attacker, *defender)
-}
-
-// CalculateDamage computes the damage dealt by the attacker.
+// CalculateDamage computes the base damage dealt by the attacker, before the variance and critical hits.
 // It adds the attacker's current strength and weapon power if applicable.
 func CalculateDamage(attacker Fighter) int {
 	damage := attacker.CurrentStrength()
@@ -97,13 +55,19 @@ func CalculateDamage(attacker Fighter) int {
 	return damage
 }
 
-// ApplyDamage applies the given damage amount to the target unit,
-// reducing its health but not allowing it to go below zero.
-func ApplyDamage(target *Unit, dmg int) {
+// ApplyDamage applies the given damage amount reduced by the target's defense to the target unit,
+// reducing its health but not allowing it to go below zero. Defense never absorbs the whole damage,
+// at least 1 point goes through. Returns the damage taken.
+func ApplyDamage(target *Unit, dmg int) int {
+	if dmg <= 0 {
+		return 0
+	}
+	dmg = max(1, dmg-target.Defense)
 	target.Health -= dmg
 	if target.Health <= 0 {
 		target.Health = 0
 	}
+	return dmg
 }
 
 // FindRoomByCoords locates the room containing the specified coordinates.
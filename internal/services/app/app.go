@@ -11,6 +11,7 @@ import (
 	"github.com/tdutanton/Rogue_Game_go/internal/adapters/secondary/render"
 	"github.com/tdutanton/Rogue_Game_go/internal/adapters/secondary/storage"
 	"github.com/tdutanton/Rogue_Game_go/internal/application/usecases"
+	"github.com/tdutanton/Rogue_Game_go/internal/domain/common"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/dungeon"
 	"github.com/tdutanton/Rogue_Game_go/internal/domain/unit"
 
@@ -23,7 +24,10 @@ import (
 
 // Run initializes the game environment, loads configuration, creates windows and UI components,
 // sets up the dungeon, and starts the main game loop, using some help functions.
-func Run() {
+// New games are seeded with seed, or with a random seed if it's 0.
+// If replay is not empty, the game starts with playback of this replay file.
+func Run(seed int64, replay string) {
+	importLegacySave()
 	initGoNcurses()
 	defer gc.End()
 
@@ -37,24 +41,29 @@ func Run() {
 	)
 
 	cfgGame := loadDungeonConfig()
-	d := storage.GenerateDungeonFromConfig(1, cfgGame, nil)
+	d := storage.GenerateDungeonFromConfig(1, cfgGame, nil, common.NewRNG(common.NewSeed()))
 	character := getCharacterFromDungeon(d)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	playerActionUC := usecases.NewPlayerActionUseCase(character, d, view, cfgGame, cancel)
-	inventoryActionUC := usecases.NewInventoryAction(playerActionUC.GetDungeon(), view)
+	playerActionUC := usecases.NewPlayerActionUseCase(character, d, view, cfgGame, cancel, seed)
+	inventoryActionUC := usecases.NewInventoryAction(playerActionUC.GetDungeon(), view, playerActionUC.Replay())
+	replayUC := usecases.NewReplayPlayback(playerActionUC, inventoryActionUC)
 
 	inputHandler := input.NewInputHandler(
 		windows.Input,
 		cancel,
 		playerActionUC,
 		inventoryActionUC,
+		replayUC,
 		windows.Main,
 	)
 
 	view.RenderMainWindow()
+	if replay != "" {
+		inputHandler.StartReplay(replay)
+	}
 	gameLoop(ctx, inputHandler)
 }
 
@@ -87,9 +96,16 @@ func createAllWindows() windows {
 	}
 }
 
+// importLegacySave - move the save of the game versions without slots into a slot, a failure keeps the old file
+func importLegacySave() {
+	if err := storage.ImportLegacySave(); err != nil {
+		log.Printf("failed to import legacy save: %v", err)
+	}
+}
+
 // loadDungeonConfig - get .yaml
 func loadDungeonConfig() *storage.Config {
-	cfgGame, err := storage.LoadDungeonConfig("configs/dungeon_config.yaml")
+	cfgGame, err := storage.LoadDungeonConfig(storage.DungeonConfigFile)
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
@@ -120,7 +136,7 @@ func gameLoop(ctx context.Context, inputHandler *input.InputHandler) {
 
 // RunDebug initializes the game environment with debug logger, loads configuration, creates windows and UI components,
 // sets up the dungeon, and starts the main game loop.
-func RunDebug() {
+func RunDebug(seed int64) {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
 		panic("CONFIG_PATH is empty")
@@ -130,6 +146,7 @@ func RunDebug() {
 
 	logger.SetSettings(cfg.LoggerInfo)
 
+	importLegacySave()
 	initGoNcurses()
 	defer gc.End()
 
@@ -141,24 +158,25 @@ func RunDebug() {
 	mainWindow, _ := CreateMainWindow()
 	view := render.NewView(infoWindow, statisticWindow, gameWindow, inventoryWindow, mainWindow)
 
-	cfgGame, err := storage.LoadDungeonConfig("configs/dungeon_config.yaml")
+	cfgGame, err := storage.LoadDungeonConfig(storage.DungeonConfigFile)
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
 	level := 1
 
-	d := storage.GenerateDungeonFromConfig(level, cfgGame, nil)
+	d := storage.GenerateDungeonFromConfig(level, cfgGame, nil, common.NewRNG(common.NewSeed()))
 	character, ok := d.Player.(*unit.Character)
 	if !ok {
 		panic("player is not of type *unit.Character")
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	playerActionUC := usecases.NewPlayerActionUseCase(character, d, view, cfgGame, cancel)
-	inventoryActionUC := usecases.NewInventoryAction(playerActionUC.GetDungeon(), view)
+	playerActionUC := usecases.NewPlayerActionUseCase(character, d, view, cfgGame, cancel, seed)
+	inventoryActionUC := usecases.NewInventoryAction(playerActionUC.GetDungeon(), view, playerActionUC.Replay())
+	replayUC := usecases.NewReplayPlayback(playerActionUC, inventoryActionUC)
 
-	inputHandler := input.NewInputHandler(inputWindow, cancel, playerActionUC, inventoryActionUC, mainWindow)
+	inputHandler := input.NewInputHandler(inputWindow, cancel, playerActionUC, inventoryActionUC, replayUC, mainWindow)
 
 	view.RenderMainWindow()
 	gameLoop(ctx, inputHandler)